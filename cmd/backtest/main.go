@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/app"
+	"github.com/tokyosplif/fraud-core/internal/usecase"
+	"github.com/tokyosplif/fraud-core/pkg/logger"
+)
+
+func main() {
+	var (
+		input    = flag.String("input", "", "NDJSON file to replay instead of the fraud_events table")
+		export   = flag.String("export", "", "write fraud_events in the selected period as NDJSON to this path (- for stdout) and exit")
+		since    = flag.String("since", "720h", "start of the evaluated period: RFC3339 time or a duration before now")
+		until    = flag.String("until", "", "end of the evaluated period: RFC3339 time or a duration before now (default now)")
+		warmup   = flag.Duration("warmup", 0, "history replayed before -since to warm up profiles and windows, excluded from metrics")
+		limit    = flag.Int("limit", 0, "maximum number of transactions to replay (0 = all)")
+		aiMode   = flag.String("ai", string(usecase.ReplayAIRecorded), "AI verdicts: recorded, allow or fail")
+		rules    = flag.String("rules", "", "rules file to evaluate (default RULES_PATH)")
+		review   = flag.Int("review", 0, "review threshold (default SCORE_REVIEW_THRESHOLD)")
+		block    = flag.Int("block", 0, "block threshold (default SCORE_BLOCK_THRESHOLD)")
+		maxDiffs = flag.Int("diffs", 20, "number of changed decisions to list")
+		features = flag.String("features", "", "write the feature snapshots of the evaluated period as NDJSON to this path (- for stdout)")
+	)
+	flag.Parse()
+
+	logLevel := os.Getenv("LOG_LEVEL")
+	if logLevel == "" {
+		logLevel = "warn"
+	}
+	logger.Setup(logLevel)
+
+	now := time.Now()
+	from, err := parseTime(*since, now)
+	if err != nil {
+		fatal("invalid -since", err)
+	}
+	to := now
+	if *until != "" {
+		if to, err = parseTime(*until, now); err != nil {
+			fatal("invalid -until", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = app.RunBacktest(ctx, app.BacktestOptions{
+		Input:           *input,
+		Export:          *export,
+		Since:           from,
+		Until:           to,
+		Warmup:          *warmup,
+		Limit:           *limit,
+		AIMode:          usecase.ReplayAIMode(*aiMode),
+		RulesPath:       *rules,
+		ReviewThreshold: *review,
+		BlockThreshold:  *block,
+		MaxDiffs:        *maxDiffs,
+		Features:        *features,
+		Out:             os.Stdout,
+	})
+	if err != nil {
+		fatal("Backtest failed", err)
+	}
+}
+
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither RFC3339 nor a duration", value)
+	}
+	return t, nil
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "err", err)
+	os.Exit(1)
+}
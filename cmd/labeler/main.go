@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/tokyosplif/fraud-core/internal/app"
+	"github.com/tokyosplif/fraud-core/pkg/logger"
+)
+
+func main() {
+	importPath := flag.String("import", "", "NDJSON file of labels to apply instead of consuming LABELS_TOPIC")
+	flag.Parse()
+
+	logLevel := os.Getenv("LOG_LEVEL")
+	if logLevel == "" {
+		logLevel = "info"
+	}
+	logger.Setup(logLevel)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	slog.Info("Starting Fraud Labeler Service...")
+
+	if err := app.RunLabeler(ctx, app.LabelerOptions{Import: *importPath}); err != nil {
+		slog.Error("Labeler fatal error", "err", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Labeler stopped gracefully")
+}
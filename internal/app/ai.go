@@ -0,0 +1,70 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/tokyosplif/fraud-core/internal/config"
+	"github.com/tokyosplif/fraud-core/internal/infrastructure/grpc_client"
+	"github.com/tokyosplif/fraud-core/internal/infrastructure/ml"
+	"github.com/tokyosplif/fraud-core/internal/usecase"
+)
+
+const (
+	aiModeRemote   = "remote"
+	aiModeLocal    = "local"
+	aiModeFallback = "fallback"
+	aiModeEnsemble = "ensemble"
+)
+
+// newAIClient builds the AI for AI_MODE: the remote risk engine, the local
+// model, the remote engine falling back to the local model, or both as a
+// weighted ensemble.
+func newAIClient(cfg *config.Config) (usecase.AIClient, io.Closer, error) {
+	switch cfg.AIMode {
+	case aiModeRemote, aiModeLocal, aiModeFallback, aiModeEnsemble:
+	default:
+		return nil, nil, fmt.Errorf("unknown AI_MODE %q", cfg.AIMode)
+	}
+
+	var model *ml.Model
+	if cfg.AIMode != aiModeRemote {
+		var err error
+		model, err = ml.LoadModel(cfg.AIModelPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("local model: %w", err)
+		}
+		slog.Info("Local risk model loaded", "model", model.Name(), "path", cfg.AIModelPath)
+	}
+	if cfg.AIMode == aiModeLocal {
+		return model, nil, nil
+	}
+
+	remote, err := grpc_client.NewRiskClient(cfg.RiskEngineAddr, grpc_client.ClientConfig{
+		Budget:           cfg.RiskEngineBudget,
+		Attempts:         cfg.RiskEngineAttempts,
+		MaxConcurrent:    cfg.RiskEngineMaxConcurrent,
+		FailureThreshold: cfg.RiskEngineBreakerFailures,
+		OpenTimeout:      cfg.RiskEngineBreakerOpen,
+		HalfOpenProbes:   cfg.RiskEngineHalfOpenProbes,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("ai client: %w", err)
+	}
+	switch cfg.AIMode {
+	case aiModeFallback:
+		return usecase.NewFallbackAI(remote, model), remote, nil
+	case aiModeEnsemble:
+		ensemble, err := usecase.NewEnsembleAI(
+			usecase.EnsembleMember{Name: "remote", Client: remote, Weight: 1 - cfg.AIEnsembleLocalWeight},
+			usecase.EnsembleMember{Name: model.Name(), Client: model, Weight: cfg.AIEnsembleLocalWeight},
+		)
+		if err != nil {
+			_ = remote.Close()
+			return nil, nil, fmt.Errorf("ai ensemble: %w", err)
+		}
+		return ensemble, remote, nil
+	}
+	return remote, remote, nil
+}
@@ -0,0 +1,208 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/config"
+	"github.com/tokyosplif/fraud-core/internal/domain"
+	"github.com/tokyosplif/fraud-core/internal/infrastructure/db"
+	"github.com/tokyosplif/fraud-core/internal/infrastructure/memory"
+	"github.com/tokyosplif/fraud-core/internal/usecase"
+	"github.com/tokyosplif/fraud-core/pkg/closer"
+)
+
+type BacktestOptions struct {
+	Input           string
+	Export          string
+	Since           time.Time
+	Until           time.Time
+	Warmup          time.Duration
+	Limit           int
+	AIMode          usecase.ReplayAIMode
+	RulesPath       string
+	ReviewThreshold int
+	BlockThreshold  int
+	MaxDiffs        int
+	Features        string
+	Out             io.Writer
+}
+
+// RunBacktest replays historical transactions through the current detection
+// pipeline on in-memory state and prints how its decisions differ from the
+// stored ones. Bans, risk updates and shadows are not part of the replay.
+func RunBacktest(ctx context.Context, opts BacktestOptions) error {
+	cfg, err := config.New()
+	if err != nil {
+		return fmt.Errorf("config init: %w", err)
+	}
+	if opts.RulesPath != "" {
+		cfg.RulesPath = opts.RulesPath
+	}
+	if opts.ReviewThreshold > 0 {
+		cfg.ScoreReviewThreshold = opts.ReviewThreshold
+	}
+	if opts.BlockThreshold > 0 {
+		cfg.ScoreBlockThreshold = opts.BlockThreshold
+	}
+
+	store := memory.NewStore()
+	var records []usecase.ReplayRecord
+	if opts.Input != "" {
+		records, err = readNDJSON[usecase.ReplayRecord](opts.Input)
+		if err != nil {
+			return err
+		}
+		records = slices.DeleteFunc(records, func(r usecase.ReplayRecord) bool {
+			return r.Timestamp.Before(opts.Since.Add(-opts.Warmup)) || !r.Timestamp.Before(opts.Until)
+		})
+		// Like the Postgres source, the limit keeps the earliest transactions.
+		slices.SortStableFunc(records, func(x, y usecase.ReplayRecord) int {
+			return x.Timestamp.Compare(y.Timestamp)
+		})
+		if opts.Limit > 0 && len(records) > opts.Limit {
+			records = records[:opts.Limit]
+		}
+	} else {
+		pgDB, sqlDB, err := openPostgres(cfg.PostgresDSN)
+		if err != nil {
+			return err
+		}
+		defer closer.Close(sqlDB, "postgres")
+		pgRepo := db.NewPostgresRepository(pgDB)
+
+		events, err := pgRepo.ListFraudEvents(ctx, opts.Since.Add(-opts.Warmup), opts.Until, opts.Limit)
+		if err != nil {
+			return fmt.Errorf("load fraud events: %w", err)
+		}
+		records = make([]usecase.ReplayRecord, len(events))
+		for i, e := range events {
+			records[i] = usecase.ReplayRecordFromEvent(e)
+		}
+		if opts.Export != "" {
+			if err := writeNDJSON(opts.Export, opts.Out, records); err != nil {
+				return err
+			}
+			slog.Info("Exported fraud events", "records", len(records), "path", opts.Export)
+			return nil
+		}
+		if err := preloadStore(ctx, pgRepo, store); err != nil {
+			return err
+		}
+	}
+
+	detectorOpts, _, err := detectorOptions(ctx, cfg, detectorStores{
+		merchants: store,
+		geo:       store,
+		ips:       store,
+		links:     store,
+		trust:     store,
+		history:   store,
+		activity:  store,
+		near:      store,
+		aml:       store,
+		state:     store,
+		amounts:   store,
+		features:  store,
+		snapshots: store,
+	})
+	if err != nil {
+		return err
+	}
+	ai, err := usecase.NewReplayAI(opts.AIMode)
+	if err != nil {
+		return err
+	}
+	alerts := &usecase.AlertRecorder{}
+	detector := usecase.NewFraudDetector(ai, store, store, alerts, detectorOpts...)
+
+	slog.Info("Backtest started", "records", len(records), "since", opts.Since, "until", opts.Until, "ai", opts.AIMode)
+	report, err := usecase.NewBacktester(detector, ai, alerts, opts.Since, opts.MaxDiffs).Run(ctx, records)
+	if err != nil {
+		return err
+	}
+	if opts.Features != "" {
+		snapshots := slices.DeleteFunc(store.FeatureSnapshots(), func(s domain.FeatureSnapshot) bool {
+			return s.At.Before(opts.Since)
+		})
+		if err := writeNDJSON(opts.Features, opts.Out, snapshots); err != nil {
+			return fmt.Errorf("write features: %w", err)
+		}
+		slog.Info("Exported feature snapshots", "snapshots", len(snapshots), "path", opts.Features)
+	}
+	return printBacktestReport(opts.Out, report)
+}
+
+// preloadStore copies users and merchants into the replay store. The
+// current risk score and ban state are the result of the very history being
+// replayed, so users start from their baseline and unbanned.
+func preloadStore(ctx context.Context, repo *db.PostgresRepository, store *memory.Store) error {
+	users, err := repo.ListUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("load users: %w", err)
+	}
+	for _, u := range users {
+		u.RiskScore = u.RiskBaseline
+		u.RiskUpdatedAt = nil
+		u.IsBanned, u.BannedAt, u.BannedBy, u.BanReason = false, nil, "", ""
+		store.PutUser(u)
+	}
+
+	merchants, err := repo.ListMerchants(ctx)
+	if err != nil {
+		return fmt.Errorf("load merchants: %w", err)
+	}
+	for _, m := range merchants {
+		if err := store.UpsertMerchant(ctx, &m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printBacktestReport(out io.Writer, r *usecase.BacktestReport) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	decisions := []domain.Decision{domain.DecisionAllow, domain.DecisionReview, domain.DecisionBlock}
+
+	fmt.Fprintf(w, "Replayed %d transactions (%d warm-up)", r.Total, r.Warmup)
+	if r.Total > 0 {
+		fmt.Fprintf(w, " from %s to %s", r.From.Format(time.RFC3339), r.To.Format(time.RFC3339))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "DECISION\tSTORED\tREPLAYED\tDELTA")
+	for _, d := range decisions {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%+d\n", d, r.Stored[d], r.Replayed[d], r.Replayed[d]-r.Stored[d])
+	}
+	fmt.Fprintln(w)
+
+	rate := 0.0
+	if r.Total > 0 {
+		rate = float64(r.Changed) * 100 / float64(r.Total)
+	}
+	fmt.Fprintf(w, "Changed decisions:\t%d (%.1f%%)\n", r.Changed, rate)
+	fmt.Fprintf(w, "Average score delta:\t%+.1f\n", r.AvgScoreDelta())
+	for _, from := range decisions {
+		for _, to := range decisions {
+			if n := r.Transitions[[2]domain.Decision{from, to}]; n > 0 {
+				fmt.Fprintf(w, "  %s -> %s\t%d\n", from, to, n)
+			}
+		}
+	}
+
+	if len(r.Diffs) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "TRANSACTION\tUSER\tAMOUNT\tSTORED\tREPLAYED\tREASON")
+		for _, d := range r.Diffs {
+			fmt.Fprintf(w, "%s\t%s\t%.2f %s\t%s (%d)\t%s (%d)\t%s\n",
+				d.TransactionID, d.UserID, d.Amount, d.Currency, d.Stored, d.StoredScore, d.Replayed, d.ReplayedScore, d.Reason)
+		}
+	}
+	return w.Flush()
+}
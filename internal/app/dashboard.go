@@ -8,10 +8,13 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/tokyosplif/fraud-core/internal/config"
 	transport "github.com/tokyosplif/fraud-core/internal/delivery/http"
 	"github.com/tokyosplif/fraud-core/internal/domain"
+	"github.com/tokyosplif/fraud-core/internal/infrastructure/db"
 	"github.com/tokyosplif/fraud-core/internal/infrastructure/kafka"
+	"github.com/tokyosplif/fraud-core/internal/usecase"
 	"github.com/tokyosplif/fraud-core/pkg/closer"
 )
 
@@ -24,10 +27,28 @@ func RunDashboard(ctx context.Context) error {
 	if len(cfg.KafkaBrokers) == 0 {
 		return fmt.Errorf("no kafka brokers configured")
 	}
-	if err := kafka.EnsureTopics(cfg.KafkaBrokers[0], cfg.KafkaTopic, cfg.AlertsTopic); err != nil {
+	if err := kafka.EnsureTopics(cfg.KafkaBrokers[0], cfg.KafkaTopic, cfg.AlertsTopic, cfg.CasesTopic); err != nil {
 		return fmt.Errorf("failed to ensure kafka topics: %w", err)
 	}
 
+	pgDB, sqlDB, err := openPostgres(cfg.PostgresDSN)
+	if err != nil {
+		return err
+	}
+	defer closer.Close(sqlDB, "postgres")
+	pgRepo := db.NewPostgresRepository(pgDB)
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+	})
+	defer closer.Close(rdb, "redis")
+	redisRepo := db.NewRedisRepository(rdb)
+	riskCache, err := newRiskCache(cfg, redisRepo)
+	if err != nil {
+		return err
+	}
+
 	hub := transport.NewHub()
 	consumer := kafka.NewConsumer[domain.FraudAlert](cfg.KafkaBrokers, cfg.AlertsTopic, cfg.DashboardGroupID)
 	defer closer.Close(consumer, "kafka.consumer")
@@ -35,6 +56,35 @@ func RunDashboard(ctx context.Context) error {
 	mux := http.NewServeMux()
 	transport.RegisterRoutes(mux, hub)
 
+	casePublisher := kafka.NewPublisher[domain.ReviewCase](cfg.KafkaBrokers, cfg.CasesTopic)
+	defer closer.Close(casePublisher, "kafka.cases")
+
+	risk := newRiskUpdater(cfg, pgRepo, riskCache)
+	// The dashboard only looks features up, so the store gets no state or
+	// velocity horizons.
+	features, err := newFeatureStore(cfg, usecase.FeatureBackends{Online: redisRepo, Snapshots: pgRepo}, nil)
+	if err != nil {
+		return err
+	}
+	admin := transport.NewAdminHandler(transport.AdminServices{
+		Bans:      usecase.NewBanService(pgRepo, usecase.AutoBanPolicy{}, riskCache),
+		Risk:      risk,
+		Merchants: usecase.NewMerchantService(pgRepo, riskCache),
+		RiskCache: riskCache,
+		Links:     newLinkGraph(cfg, pgRepo),
+		Shadows:   usecase.NewShadowService(pgRepo),
+		Trust:     usecase.NewTrustService(pgRepo, trustConfig(cfg)),
+		AML:       usecase.NewAMLService(pgRepo),
+		Features:  features,
+		Cases:     usecase.NewCaseService(pgRepo, casePublisher, risk),
+		Labels:    usecase.NewLabelService(pgRepo, risk),
+	})
+	if cfg.AdminToken != "" {
+		transport.RegisterAdminRoutes(mux, admin, cfg.AdminToken)
+	} else {
+		slog.Warn("ADMIN_TOKEN is not set, admin API disabled")
+	}
+
 	server := &http.Server{
 		Addr:    cfg.DashboardPort,
 		Handler: mux,
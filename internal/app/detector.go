@@ -0,0 +1,182 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/tokyosplif/fraud-core/internal/config"
+	"github.com/tokyosplif/fraud-core/internal/domain"
+	"github.com/tokyosplif/fraud-core/internal/infrastructure/fx"
+	"github.com/tokyosplif/fraud-core/internal/infrastructure/geo"
+	"github.com/tokyosplif/fraud-core/internal/infrastructure/ipintel"
+	"github.com/tokyosplif/fraud-core/internal/usecase"
+)
+
+// detectorStores are the state backends of the stateful detectors. The
+// processor uses Postgres and Redis; the backtest uses memory.Store.
+type detectorStores struct {
+	merchants usecase.MerchantRepository
+	geo       usecase.GeoStore
+	ips       usecase.IPVelocityStore
+	links     usecase.LinkRepository
+	trust     usecase.TrustRepository
+	history   usecase.HistoryStore
+	activity  usecase.ActivityStore
+	near      usecase.StructuringStore
+	aml       usecase.AMLCaseRepository
+	state     usecase.CacheRepository
+	amounts   usecase.AmountHistory
+	features  usecase.OnlineFeatureStore
+	snapshots usecase.FeatureSnapshotRepository
+}
+
+// detectorOptions composes the detection pipeline shared by the processor
+// and the backtest: rules, degraded policy, scoring, velocity horizons,
+// amount anomaly, detectors, the feature store and FX normalization.
+func detectorOptions(ctx context.Context, cfg *config.Config, stores detectorStores) ([]usecase.DetectorOption, usecase.ScoringConfig, error) {
+	rules := usecase.DefaultRules()
+	if cfg.RulesPath != "" {
+		var err error
+		rules, err = usecase.LoadRules(cfg.RulesPath)
+		if err != nil {
+			return nil, usecase.ScoringConfig{}, fmt.Errorf("rules: %w", err)
+		}
+	}
+	ruleEngine, err := usecase.NewRuleEngine(rules)
+	if err != nil {
+		return nil, usecase.ScoringConfig{}, fmt.Errorf("rules: %w", err)
+	}
+	slog.Info("Rule engine loaded", "rules", len(rules), "path", cfg.RulesPath)
+
+	degradedCfg := usecase.DefaultDegradedPolicyConfig()
+	if cfg.DegradedPolicyPath != "" {
+		degradedCfg, err = usecase.LoadDegradedPolicy(cfg.DegradedPolicyPath)
+		if err != nil {
+			return nil, usecase.ScoringConfig{}, err
+		}
+	}
+	degraded, err := usecase.NewDegradedPolicy(degradedCfg)
+	if err != nil {
+		return nil, usecase.ScoringConfig{}, fmt.Errorf("degraded policy: %w", err)
+	}
+	slog.Info("Degraded policy loaded", "rules", len(degradedCfg.Rules), "default", degradedCfg.Default, "path", cfg.DegradedPolicyPath)
+
+	scoring := usecase.ScoringConfig{
+		Weights:         cfg.ScoreWeights,
+		VelocityLimit:   cfg.VelocityLimit,
+		ReviewThreshold: cfg.ScoreReviewThreshold,
+		BlockThreshold:  cfg.ScoreBlockThreshold,
+	}
+	if len(cfg.TierThresholds) > 0 {
+		scoring.TierThresholds = make(map[domain.MerchantRiskTier]usecase.Thresholds, len(cfg.TierThresholds))
+		for tier, t := range cfg.TierThresholds {
+			scoring.TierThresholds[domain.MerchantRiskTier(tier)] = usecase.Thresholds{Review: t[0], Block: t[1]}
+		}
+	}
+	scorer, err := usecase.NewScorer(scoring)
+	if err != nil {
+		return nil, scoring, fmt.Errorf("scorer: %w", err)
+	}
+
+	horizons, err := usecase.ParseVelocityHorizons(cfg.VelocityHorizons)
+	if err != nil {
+		return nil, scoring, fmt.Errorf("velocity: %w", err)
+	}
+
+	cardTesting := usecase.CardTestingConfig{
+		Window:       cfg.CardTestingWindow,
+		SmallAmount:  cfg.CardTestingSmallAmount,
+		MinSmall:     cfg.CardTestingMinSmall,
+		MinRising:    cfg.CardTestingMinRising,
+		MinMerchants: cfg.CardTestingMinMerchants,
+	}
+	if longest := horizons[len(horizons)-1].Window; cardTesting.Window > longest {
+		return nil, scoring, fmt.Errorf("card testing window %s exceeds the longest velocity horizon %s", cardTesting.Window, longest)
+	}
+
+	anomaly := usecase.DefaultAmountAnomalyConfig()
+	anomaly.MinSamples = int64(cfg.AmountMinSamples)
+	anomaly.ZScoreThreshold = cfg.AmountZScoreThreshold
+
+	ipReputation, err := ipintel.NewReputation(ipintel.Paths{
+		Allow: cfg.IPAllowlistPath,
+		Deny:  cfg.IPDenylistPath,
+		Proxy: cfg.IPProxyListPath,
+		Tor:   cfg.IPTorListPath,
+	})
+	if err != nil {
+		return nil, scoring, fmt.Errorf("ip lists: %w", err)
+	}
+	allow, deny, proxy, tor := ipReputation.Sizes()
+	slog.Info("IP reputation lists loaded", "allow", allow, "deny", deny, "proxy", proxy, "tor", tor)
+
+	structuring := usecase.StructuringConfig{
+		Thresholds:     cfg.AMLThresholds,
+		Margin:         cfg.AMLMargin,
+		Period:         cfg.AMLPeriod,
+		MinCount:       cfg.AMLMinCount,
+		MinLinkedCount: cfg.AMLMinLinkedCount,
+	}
+	if structuring.Margin <= 0 || structuring.Margin >= 1 {
+		return nil, scoring, fmt.Errorf("aml margin %v must be in (0, 1)", structuring.Margin)
+	}
+
+	resolver := geo.NewCityResolver()
+	graph := newLinkGraph(cfg, stores.links)
+	opts := []usecase.DetectorOption{
+		usecase.WithRuleEngine(ruleEngine),
+		usecase.WithScorer(scorer),
+		usecase.WithAmountAnomaly(anomaly),
+		usecase.WithDegradedPolicy(degraded),
+		usecase.WithDetectors(
+			usecase.NewMerchantDetector(stores.merchants),
+			usecase.NewImpossibleTravelDetector(resolver, stores.geo, cfg.MaxTravelSpeedKmh),
+			usecase.NewIPIntelDetector(ipReputation, stores.ips, usecase.IPIntelConfig{
+				Window:      cfg.IPWindow,
+				MaxUsers:    cfg.IPMaxUsers,
+				MaxTxPerWin: cfg.IPMaxTx,
+			}),
+			usecase.NewRingDetector(graph),
+			usecase.NewTrustDetector(stores.trust, trustConfig(cfg)),
+			usecase.NewCardTestingDetector(stores.activity, cardTesting),
+			usecase.NewStructuringDetector(stores.near, stores.aml, graph, structuring),
+		),
+	}
+	opts = append(opts, usecase.WithEntityHistory(usecase.NewEntityHistory(stores.history, resolver)))
+
+	features, err := newFeatureStore(cfg, usecase.FeatureBackends{
+		State:     stores.state,
+		Amounts:   stores.amounts,
+		Online:    stores.features,
+		Snapshots: stores.snapshots,
+	}, horizons)
+	if err != nil {
+		return nil, scoring, err
+	}
+	opts = append(opts, usecase.WithFeatureStore(features))
+	slog.Info("Feature store enabled", "version", features.Version(), "definitions", len(features.Definitions()))
+	if cfg.TrustSkipAI {
+		opts = append(opts, usecase.WithTrustedAISkip())
+	}
+
+	if cfg.FXRatesPath != "" {
+		rates, err := fx.NewRates(cfg.FXRatesPath, cfg.FXBaseCurrency)
+		if err != nil {
+			return nil, scoring, fmt.Errorf("fx rates: %w", err)
+		}
+		go rates.Run(ctx, cfg.FXRefreshInterval)
+		opts = append(opts, usecase.WithCurrencyNormalizer(usecase.NewCurrencyNormalizer(rates)))
+		slog.Info("FX rates loaded", "base", rates.Base(), "path", cfg.FXRatesPath, "refresh", cfg.FXRefreshInterval)
+	}
+
+	return opts, scoring, nil
+}
+
+func newFeatureStore(cfg *config.Config, backends usecase.FeatureBackends, horizons []usecase.VelocityHorizon) (*usecase.FeatureStore, error) {
+	registry, err := usecase.NewFeatureRegistry(usecase.DefaultFeatureDefinitions())
+	if err != nil {
+		return nil, fmt.Errorf("features: %w", err)
+	}
+	return usecase.NewFeatureStore(registry, backends, horizons, cfg.FeatureRetention), nil
+}
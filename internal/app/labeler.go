@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/tokyosplif/fraud-core/internal/config"
+	"github.com/tokyosplif/fraud-core/internal/domain"
+	"github.com/tokyosplif/fraud-core/internal/infrastructure/db"
+	"github.com/tokyosplif/fraud-core/internal/infrastructure/kafka"
+	"github.com/tokyosplif/fraud-core/internal/usecase"
+	"github.com/tokyosplif/fraud-core/pkg/closer"
+)
+
+type LabelerOptions struct {
+	// Import is an NDJSON file of labels to apply instead of consuming the
+	// labels topic.
+	Import string
+}
+
+// RunLabeler attaches fraud labels to stored decisions, either from the
+// labels topic or from a one-off NDJSON import, and reports precision and
+// recall over LABEL_METRICS_WINDOW.
+func RunLabeler(ctx context.Context, opts LabelerOptions) error {
+	cfg, err := config.New()
+	if err != nil {
+		return fmt.Errorf("config init: %w", err)
+	}
+
+	pgDB, sqlDB, err := openPostgres(cfg.PostgresDSN)
+	if err != nil {
+		return err
+	}
+	defer closer.Close(sqlDB, "postgres")
+
+	if err := db.Migrate(pgDB); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+
+	pgRepo := db.NewPostgresRepository(pgDB)
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+	})
+	defer closer.Close(rdb, "redis")
+	riskCache, err := newRiskCache(cfg, db.NewRedisRepository(rdb))
+	if err != nil {
+		return err
+	}
+	labels := usecase.NewLabelService(pgRepo, newRiskUpdater(cfg, pgRepo, riskCache))
+
+	if opts.Import != "" {
+		return importLabels(ctx, labels, opts.Import, cfg.LabelMetricsWindow)
+	}
+
+	if len(cfg.KafkaBrokers) == 0 {
+		return fmt.Errorf("no kafka brokers configured")
+	}
+	if err := kafka.EnsureTopics(cfg.KafkaBrokers[0], cfg.LabelsTopic); err != nil {
+		return fmt.Errorf("failed to ensure kafka topics: %w", err)
+	}
+
+	consumer := kafka.NewConsumer[domain.FraudLabel](cfg.KafkaBrokers, cfg.LabelsTopic, cfg.LabelerGroupID)
+	defer closer.Close(consumer, "kafka.consumer")
+
+	if cfg.LabelMetricsInterval > 0 {
+		go reportLabelMetrics(ctx, labels, cfg.LabelMetricsWindow, cfg.LabelMetricsInterval)
+	}
+
+	slog.Info("Fraud labeler started", "topic", cfg.LabelsTopic)
+
+	return consumer.Consume(ctx, func(ctx context.Context, l domain.FraudLabel) error {
+		_, err := labels.Apply(ctx, l)
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrInvalidInput) {
+			slog.Warn("Skipping fraud label", "tx_id", l.TransactionID, "err", err)
+			return nil
+		}
+		return err
+	})
+}
+
+func importLabels(ctx context.Context, labels *usecase.LabelService, path string, window time.Duration) error {
+	items, err := readNDJSON[domain.FraudLabel](path)
+	if err != nil {
+		return err
+	}
+
+	var applied, unchanged, skipped int
+	for _, l := range items {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		changed, err := labels.Apply(ctx, l)
+		switch {
+		case errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrInvalidInput):
+			slog.Warn("Skipping fraud label", "tx_id", l.TransactionID, "err", err)
+			skipped++
+		case err != nil:
+			return err
+		case changed:
+			applied++
+		default:
+			unchanged++
+		}
+	}
+	slog.Info("Imported fraud labels", "path", path, "applied", applied, "unchanged", unchanged, "skipped", skipped)
+
+	logLabelMetrics(ctx, labels, window)
+	return nil
+}
+
+func reportLabelMetrics(ctx context.Context, labels *usecase.LabelService, window, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			logLabelMetrics(ctx, labels, window)
+		}
+	}
+}
+
+func logLabelMetrics(ctx context.Context, labels *usecase.LabelService, window time.Duration) {
+	m, err := labels.Metrics(ctx, window)
+	if err != nil {
+		slog.Warn("Failed to compute label metrics", "err", err)
+		return
+	}
+	slog.Info("Decision quality",
+		"window", m.Window,
+		"decisions", m.Decisions,
+		"labeled", m.Labeled,
+		"precision", m.Precision,
+		"recall", m.Recall,
+		"true_positives", m.TruePositives,
+		"false_positives", m.FalsePositives,
+		"false_negatives", m.FalseNegatives,
+	)
+}
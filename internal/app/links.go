@@ -0,0 +1,15 @@
+package app
+
+import (
+	"github.com/tokyosplif/fraud-core/internal/config"
+	"github.com/tokyosplif/fraud-core/internal/usecase"
+)
+
+func newLinkGraph(cfg *config.Config, repo usecase.LinkRepository) *usecase.LinkGraph {
+	graph := usecase.DefaultLinkGraphConfig()
+	graph.Window = cfg.RingWindow
+	graph.MaxDepth = cfg.RingMaxDepth
+	graph.MaxUsers = cfg.RingMaxUsers
+	graph.MinRingUsers = cfg.RingMinUsers
+	return usecase.NewLinkGraph(repo, graph)
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// serveMetrics exposes expvar counters (risk engine breaker state, call and
+// rejection counts) on addr until ctx is done.
+func serveMetrics(ctx context.Context, addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("Metrics server started", "addr", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("Metrics server failed", "err", err)
+	}
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	dbMaxRetries      = 30
+	dbMaxOpenConns    = 25
+	dbMaxIdleConns    = 5
+	dbConnMaxLifetime = time.Hour
+	dbRetryDelay      = 1 * time.Second
+)
+
+func openPostgres(dsn string) (*gorm.DB, *sql.DB, error) {
+	var pgDB *gorm.DB
+	var err error
+	for i := 0; i < dbMaxRetries; i++ {
+		pgDB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		if err == nil {
+			break
+		}
+		slog.Warn("Waiting for database...", "attempt", i+1, "max", dbMaxRetries)
+		time.Sleep(dbRetryDelay)
+	}
+	if err != nil || pgDB == nil {
+		return nil, nil, fmt.Errorf("postgres failure after %d attempts: %w", dbMaxRetries, err)
+	}
+
+	sqlDB, err := pgDB.DB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get sql db to configure pool: %w", err)
+	}
+
+	sqlDB.SetMaxOpenConns(dbMaxOpenConns)
+	sqlDB.SetMaxIdleConns(dbMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(dbConnMaxLifetime)
+
+	slog.Info("Postgres connection pool configured",
+		"max_open", dbMaxOpenConns,
+		"max_idle", dbMaxIdleConns,
+	)
+
+	return pgDB, sqlDB, nil
+}
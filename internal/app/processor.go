@@ -4,27 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/tokyosplif/fraud-core/internal/config"
 	"github.com/tokyosplif/fraud-core/internal/domain"
 	"github.com/tokyosplif/fraud-core/internal/infrastructure/db"
-	"github.com/tokyosplif/fraud-core/internal/infrastructure/grpc_client"
 	"github.com/tokyosplif/fraud-core/internal/infrastructure/kafka"
 	"github.com/tokyosplif/fraud-core/internal/usecase"
 	"github.com/tokyosplif/fraud-core/pkg/closer"
-
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
-)
-
-const (
-	dbMaxRetries      = 30
-	dbMaxOpenConns    = 25
-	dbMaxIdleConns    = 5
-	dbConnMaxLifetime = time.Hour
-	dbRetryDelay      = 1 * time.Second
 )
 
 func RunProcessor(ctx context.Context) error {
@@ -33,20 +20,13 @@ func RunProcessor(ctx context.Context) error {
 		return fmt.Errorf("config init: %w", err)
 	}
 
-	var pgDB *gorm.DB
-	for i := 0; i < dbMaxRetries; i++ {
-		pgDB, err = gorm.Open(postgres.Open(cfg.PostgresDSN), &gorm.Config{})
-		if err == nil {
-			break
-		}
-		slog.Warn("Waiting for database...", "attempt", i+1, "max", dbMaxRetries)
-		time.Sleep(dbRetryDelay)
-	}
-	if err != nil || pgDB == nil {
-		return fmt.Errorf("postgres failure after %d attempts: %w", dbMaxRetries, err)
+	pgDB, sqlDB, err := openPostgres(cfg.PostgresDSN)
+	if err != nil {
+		return err
 	}
+	defer closer.Close(sqlDB, "postgres")
 
-	if err := pgDB.AutoMigrate(&domain.User{}, &domain.FraudEvent{}); err != nil {
+	if err := db.Migrate(pgDB); err != nil {
 		return fmt.Errorf("migration failed: %w", err)
 	}
 
@@ -54,22 +34,6 @@ func RunProcessor(ctx context.Context) error {
 		slog.Error("Seeding failed, but continuing execution", "err", err)
 	}
 
-	sqlDB, err := pgDB.DB()
-	if err != nil {
-		return fmt.Errorf("failed to get sql db to configure pool: %w", err)
-	}
-
-	sqlDB.SetMaxOpenConns(dbMaxOpenConns)
-	sqlDB.SetMaxIdleConns(dbMaxIdleConns)
-	sqlDB.SetConnMaxLifetime(dbConnMaxLifetime)
-
-	defer closer.Close(sqlDB, "postgres")
-
-	slog.Info("Postgres connection pool configured",
-		"max_open", dbMaxOpenConns,
-		"max_idle", dbMaxIdleConns,
-	)
-
 	pgRepo := db.NewPostgresRepository(pgDB)
 
 	rdb := redis.NewClient(&redis.Options{
@@ -79,23 +43,68 @@ func RunProcessor(ctx context.Context) error {
 	defer closer.Close(rdb, "redis")
 	redisRepo := db.NewRedisRepository(rdb)
 
-	aiClient, err := grpc_client.NewRiskClient(cfg.RiskEngineAddr)
+	if cfg.MetricsAddr != "" {
+		go serveMetrics(ctx, cfg.MetricsAddr)
+	}
+
+	aiClient, aiCloser, err := newAIClient(cfg)
 	if err != nil {
-		return fmt.Errorf("ai client: %w", err)
+		return err
 	}
-	defer closer.Close(aiClient, "risk.client")
+	defer closer.Close(aiCloser, "risk.client")
 
 	if len(cfg.KafkaBrokers) == 0 {
 		return fmt.Errorf("no kafka brokers configured")
 	}
-	if err := kafka.EnsureTopics(cfg.KafkaBrokers[0], cfg.KafkaTopic, cfg.AlertsTopic); err != nil {
+	if err := kafka.EnsureTopics(cfg.KafkaBrokers[0], cfg.KafkaTopic, cfg.AlertsTopic, cfg.CasesTopic); err != nil {
 		return fmt.Errorf("failed to ensure kafka topics: %w", err)
 	}
 
 	publisher := kafka.NewPublisher[domain.FraudAlert](cfg.KafkaBrokers, cfg.AlertsTopic)
 	defer closer.Close(publisher, "kafka.publisher")
 
-	detector := usecase.NewFraudDetector(aiClient, pgRepo, redisRepo, publisher)
+	opts, scoring, err := detectorOptions(ctx, cfg, detectorStores{
+		merchants: pgRepo,
+		geo:       redisRepo,
+		ips:       redisRepo,
+		links:     pgRepo,
+		trust:     pgRepo,
+		history:   redisRepo,
+		activity:  redisRepo,
+		near:      redisRepo,
+		aml:       pgRepo,
+		state:     redisRepo,
+		amounts:   pgRepo,
+		features:  redisRepo,
+		snapshots: pgRepo,
+	})
+	if err != nil {
+		return err
+	}
+	riskCache, err := newRiskCache(cfg, redisRepo)
+	if err != nil {
+		return err
+	}
+	opts = append(opts,
+		usecase.WithRiskCache(riskCache),
+		usecase.WithBanService(usecase.NewBanService(pgRepo, usecase.AutoBanPolicy{
+			MaxBlocks: cfg.AutoBanMaxBlocks,
+			Window:    cfg.AutoBanWindow,
+		}, riskCache)),
+		usecase.WithRiskUpdater(newRiskUpdater(cfg, pgRepo, riskCache)),
+		usecase.WithCaseQueue(usecase.NewCaseService(pgRepo, nil, nil)),
+	)
+
+	shadows, err := newShadows(cfg, scoring)
+	if err != nil {
+		return err
+	}
+	if len(shadows) > 0 {
+		opts = append(opts, usecase.WithShadows(pgRepo, shadows...))
+		slog.Info("Shadow evaluators registered", "count", len(shadows))
+	}
+
+	detector := usecase.NewFraudDetector(aiClient, pgRepo, redisRepo, publisher, opts...)
 
 	consumer := kafka.NewConsumer[domain.Transaction](cfg.KafkaBrokers, cfg.KafkaTopic, cfg.ProcessorGroupID)
 	defer closer.Close(consumer, "kafka.consumer")
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tokyosplif/fraud-core/internal/config"
+	"github.com/tokyosplif/fraud-core/internal/domain"
+	"github.com/tokyosplif/fraud-core/internal/usecase"
+)
+
+func newRiskUpdater(cfg *config.Config, repo usecase.RiskRepository, cache *usecase.RiskCache) *usecase.RiskUpdater {
+	policy := usecase.DefaultRiskPolicy()
+	policy.BlockDelta = cfg.RiskBlockDelta
+	policy.ReviewDelta = cfg.RiskReviewDelta
+	policy.CleanDelta = cfg.RiskCleanDelta
+	policy.CleanInterval = cfg.RiskCleanInterval
+	policy.QuietPeriod = cfg.RiskQuietPeriod
+	policy.HalfLife = cfg.RiskHalfLife
+	return usecase.NewRiskUpdater(repo, policy, cache)
+}
+
+func newRiskCache(cfg *config.Config, store usecase.RiskCacheStore) (*usecase.RiskCache, error) {
+	rc := usecase.DefaultRiskCacheConfig()
+	rc.AmountBands = cfg.RiskCacheAmountBands
+	for decision, ttl := range cfg.RiskCacheTTLs {
+		rc.TTLs[domain.Decision(strings.ToUpper(decision))] = ttl
+	}
+	cache, err := usecase.NewRiskCache(store, rc)
+	if err != nil {
+		return nil, fmt.Errorf("risk cache: %w", err)
+	}
+	return cache, nil
+}
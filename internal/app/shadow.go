@@ -0,0 +1,54 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tokyosplif/fraud-core/internal/config"
+	"github.com/tokyosplif/fraud-core/internal/usecase"
+)
+
+// newShadows builds one shadow per name in SHADOW_RULES and SHADOW_THRESHOLDS.
+// A name present in both gets the candidate rules and thresholds together.
+func newShadows(cfg *config.Config, scoring usecase.ScoringConfig) ([]usecase.Shadow, error) {
+	byName := make(map[string]*usecase.Shadow)
+	shadow := func(name string) *usecase.Shadow {
+		if s, ok := byName[name]; ok {
+			return s
+		}
+		s := &usecase.Shadow{Name: name}
+		byName[name] = s
+		return s
+	}
+
+	for name, path := range cfg.ShadowRules {
+		rules, err := usecase.LoadRules(path)
+		if err != nil {
+			return nil, fmt.Errorf("shadow %s: %w", name, err)
+		}
+		engine, err := usecase.NewRuleEngine(rules)
+		if err != nil {
+			return nil, fmt.Errorf("shadow %s: %w", name, err)
+		}
+		shadow(name).Rules = engine
+	}
+	for name, t := range cfg.ShadowThresholds {
+		sc := scoring
+		sc.ReviewThreshold, sc.BlockThreshold = t[0], t[1]
+		scorer, err := usecase.NewScorer(sc)
+		if err != nil {
+			return nil, fmt.Errorf("shadow %s: %w", name, err)
+		}
+		shadow(name).Scorer = scorer
+	}
+
+	shadows := make([]usecase.Shadow, 0, len(byName))
+	for _, s := range byName {
+		shadows = append(shadows, *s)
+	}
+	slices.SortFunc(shadows, func(a, b usecase.Shadow) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return shadows, nil
+}
@@ -0,0 +1,13 @@
+package app
+
+import (
+	"github.com/tokyosplif/fraud-core/internal/config"
+	"github.com/tokyosplif/fraud-core/internal/usecase"
+)
+
+func trustConfig(cfg *config.Config) usecase.TrustConfig {
+	trust := usecase.DefaultTrustConfig()
+	trust.MinClean = int64(cfg.TrustMinClean)
+	trust.TTL = cfg.TrustTTL
+	return trust
+}
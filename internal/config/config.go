@@ -3,34 +3,206 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
-	KafkaBrokers     []string
-	KafkaTopic       string
-	AlertsTopic      string
-	RedisAddr        string
-	RedisPassword    string
-	PostgresDSN      string
-	RiskEngineAddr   string
-	DashboardPort    string
-	DashboardGroupID string
-	ProcessorGroupID string
+	KafkaBrokers       []string
+	KafkaTopic         string
+	AlertsTopic        string
+	CasesTopic         string
+	LabelsTopic        string
+	RedisAddr          string
+	RedisPassword      string
+	PostgresDSN        string
+	RiskEngineAddr     string
+	DashboardPort      string
+	DashboardGroupID   string
+	ProcessorGroupID   string
+	LabelerGroupID     string
+	RulesPath          string
+	DegradedPolicyPath string
+
+	ScoreWeights         map[string]float64
+	ScoreReviewThreshold int
+	ScoreBlockThreshold  int
+	VelocityLimit        int
+	VelocityHorizons     string
+	TierThresholds       map[string][2]int
+
+	MaxTravelSpeedKmh float64
+
+	AmountMinSamples      int
+	AmountZScoreThreshold float64
+
+	AutoBanMaxBlocks int
+	AutoBanWindow    time.Duration
+	AdminToken       string
+
+	RiskBlockDelta    int
+	RiskReviewDelta   int
+	RiskCleanDelta    int
+	RiskCleanInterval time.Duration
+	RiskQuietPeriod   time.Duration
+	RiskHalfLife      time.Duration
+
+	IPAllowlistPath string
+	IPDenylistPath  string
+	IPProxyListPath string
+	IPTorListPath   string
+	IPWindow        time.Duration
+	IPMaxUsers      int
+	IPMaxTx         int
+
+	ShadowRules      map[string]string
+	ShadowThresholds map[string][2]int
+
+	RingWindow   time.Duration
+	RingMaxDepth int
+	RingMaxUsers int
+	RingMinUsers int
+
+	TrustMinClean int
+	TrustTTL      time.Duration
+	TrustSkipAI   bool
+
+	CardTestingWindow       time.Duration
+	CardTestingSmallAmount  float64
+	CardTestingMinSmall     int
+	CardTestingMinRising    int
+	CardTestingMinMerchants int
+
+	AMLThresholds     []float64
+	AMLMargin         float64
+	AMLPeriod         time.Duration
+	AMLMinCount       int
+	AMLMinLinkedCount int
+
+	FXRatesPath       string
+	FXBaseCurrency    string
+	FXRefreshInterval time.Duration
+
+	FeatureRetention time.Duration
+
+	LabelMetricsWindow   time.Duration
+	LabelMetricsInterval time.Duration
+
+	AIMode                string
+	AIModelPath           string
+	AIEnsembleLocalWeight float64
+
+	RiskEngineBudget          time.Duration
+	RiskEngineAttempts        int
+	RiskEngineMaxConcurrent   int
+	RiskEngineBreakerFailures int
+	RiskEngineBreakerOpen     time.Duration
+	RiskEngineHalfOpenProbes  int
+
+	RiskCacheAmountBands []float64
+	RiskCacheTTLs        map[string]time.Duration
+
+	MetricsAddr string
 }
 
 func New() (*Config, error) {
 	cfg := &Config{
-		KafkaBrokers:     strings.Split(getEnv("KAFKA_BROKERS", "kafka:9092"), ","),
-		KafkaTopic:       getEnv("KAFKA_TOPIC", "raw-transactions"),
-		AlertsTopic:      getEnv("ALERTS_TOPIC", "fraud-alerts"),
-		RedisAddr:        getEnv("REDIS_ADDR", "redis:6379"),
-		RedisPassword:    os.Getenv("REDIS_PASSWORD"),
-		PostgresDSN:      getEnv("POSTGRES_DSN", "host=postgres port=5432 user=user password=password dbname=fraud_radar sslmode=disable"),
-		RiskEngineAddr:   getEnv("RISK_ENGINE_ADDR", "ai-risk-engine:50051"),
-		DashboardPort:    getEnv("DASHBOARD_PORT", ":8080"),
-		DashboardGroupID: getEnv("DASHBOARD_GROUP_ID", "dashboard-group"),
-		ProcessorGroupID: getEnv("PROCESSOR_GROUP_ID", "fraud-processor-v3"),
+		KafkaBrokers:       strings.Split(getEnv("KAFKA_BROKERS", "kafka:9092"), ","),
+		KafkaTopic:         getEnv("KAFKA_TOPIC", "raw-transactions"),
+		AlertsTopic:        getEnv("ALERTS_TOPIC", "fraud-alerts"),
+		CasesTopic:         getEnv("CASES_TOPIC", "review-cases-resolved"),
+		LabelsTopic:        getEnv("LABELS_TOPIC", "fraud-labels"),
+		RedisAddr:          getEnv("REDIS_ADDR", "redis:6379"),
+		RedisPassword:      os.Getenv("REDIS_PASSWORD"),
+		PostgresDSN:        getEnv("POSTGRES_DSN", "host=postgres port=5432 user=user password=password dbname=fraud_radar sslmode=disable"),
+		RiskEngineAddr:     getEnv("RISK_ENGINE_ADDR", "ai-risk-engine:50051"),
+		DashboardPort:      getEnv("DASHBOARD_PORT", ":8080"),
+		DashboardGroupID:   getEnv("DASHBOARD_GROUP_ID", "dashboard-group"),
+		ProcessorGroupID:   getEnv("PROCESSOR_GROUP_ID", "fraud-processor-v3"),
+		LabelerGroupID:     getEnv("LABELER_GROUP_ID", "fraud-labeler"),
+		RulesPath:          os.Getenv("RULES_PATH"),
+		DegradedPolicyPath: os.Getenv("DEGRADED_POLICY_PATH"),
+
+		ScoreWeights:         getEnvWeights("SCORE_WEIGHTS"),
+		ScoreReviewThreshold: getEnvInt("SCORE_REVIEW_THRESHOLD", 40),
+		ScoreBlockThreshold:  getEnvInt("SCORE_BLOCK_THRESHOLD", 70),
+		VelocityLimit:        getEnvInt("VELOCITY_LIMIT", 10),
+		VelocityHorizons:     getEnv("VELOCITY_HORIZONS", "1m,1h,24h"),
+		TierThresholds:       getEnvThresholds("MERCHANT_TIER_THRESHOLDS"),
+
+		MaxTravelSpeedKmh: getEnvFloat("MAX_TRAVEL_SPEED_KMH", 1000),
+
+		AmountMinSamples:      getEnvInt("AMOUNT_MIN_SAMPLES", 5),
+		AmountZScoreThreshold: getEnvFloat("AMOUNT_ZSCORE_THRESHOLD", 3),
+
+		AutoBanMaxBlocks: getEnvInt("AUTO_BAN_MAX_BLOCKS", 3),
+		AutoBanWindow:    getEnvDuration("AUTO_BAN_WINDOW", time.Hour),
+		AdminToken:       os.Getenv("ADMIN_TOKEN"),
+
+		RiskBlockDelta:    getEnvInt("RISK_BLOCK_DELTA", 10),
+		RiskReviewDelta:   getEnvInt("RISK_REVIEW_DELTA", 4),
+		RiskCleanDelta:    getEnvInt("RISK_CLEAN_DELTA", -2),
+		RiskCleanInterval: getEnvDuration("RISK_CLEAN_INTERVAL", 24*time.Hour),
+		RiskQuietPeriod:   getEnvDuration("RISK_QUIET_PERIOD", 7*24*time.Hour),
+		RiskHalfLife:      getEnvDuration("RISK_HALF_LIFE", 30*24*time.Hour),
+
+		IPAllowlistPath: os.Getenv("IP_ALLOWLIST_PATH"),
+		IPDenylistPath:  os.Getenv("IP_DENYLIST_PATH"),
+		IPProxyListPath: os.Getenv("IP_PROXY_LIST_PATH"),
+		IPTorListPath:   os.Getenv("IP_TOR_LIST_PATH"),
+		IPWindow:        getEnvDuration("IP_VELOCITY_WINDOW", time.Hour),
+		IPMaxUsers:      getEnvInt("IP_MAX_USERS", 5),
+		IPMaxTx:         getEnvInt("IP_MAX_TX", 100),
+
+		ShadowRules:      getEnvPairs("SHADOW_RULES"),
+		ShadowThresholds: getEnvThresholds("SHADOW_THRESHOLDS"),
+
+		RingWindow:   getEnvDuration("RING_WINDOW", 30*24*time.Hour),
+		RingMaxDepth: getEnvInt("RING_MAX_DEPTH", 2),
+		RingMaxUsers: getEnvInt("RING_MAX_USERS", 100),
+		RingMinUsers: getEnvInt("RING_MIN_USERS", 3),
+
+		TrustMinClean: getEnvInt("TRUST_MIN_CLEAN", 5),
+		TrustTTL:      getEnvDuration("TRUST_TTL", 90*24*time.Hour),
+		TrustSkipAI:   getEnvBool("TRUST_SKIP_AI", true),
+
+		CardTestingWindow:       getEnvDuration("CARD_TESTING_WINDOW", 10*time.Minute),
+		CardTestingSmallAmount:  getEnvFloat("CARD_TESTING_SMALL_AMOUNT", 5),
+		CardTestingMinSmall:     getEnvInt("CARD_TESTING_MIN_SMALL", 3),
+		CardTestingMinRising:    getEnvInt("CARD_TESTING_MIN_RISING", 4),
+		CardTestingMinMerchants: getEnvInt("CARD_TESTING_MIN_MERCHANTS", 4),
+
+		AMLThresholds:     getEnvFloats("AML_THRESHOLDS", []float64{10000}),
+		AMLMargin:         getEnvFloat("AML_MARGIN", 0.1),
+		AMLPeriod:         getEnvDuration("AML_PERIOD", 7*24*time.Hour),
+		AMLMinCount:       getEnvInt("AML_MIN_COUNT", 3),
+		AMLMinLinkedCount: getEnvInt("AML_MIN_LINKED_COUNT", 5),
+
+		FXRatesPath:       os.Getenv("FX_RATES_PATH"),
+		FXBaseCurrency:    strings.ToUpper(getEnv("FX_BASE_CURRENCY", "USD")),
+		FXRefreshInterval: getEnvDuration("FX_REFRESH_INTERVAL", time.Hour),
+
+		FeatureRetention: getEnvDuration("FEATURE_RETENTION", 30*24*time.Hour),
+
+		LabelMetricsWindow:   getEnvDuration("LABEL_METRICS_WINDOW", 30*24*time.Hour),
+		LabelMetricsInterval: getEnvDuration("LABEL_METRICS_INTERVAL", time.Hour),
+
+		AIMode:                strings.ToLower(getEnv("AI_MODE", "remote")),
+		AIModelPath:           getEnv("AI_MODEL_PATH", "configs/risk_model.json"),
+		AIEnsembleLocalWeight: getEnvFloat("AI_ENSEMBLE_LOCAL_WEIGHT", 0.3),
+
+		RiskEngineBudget:          getEnvDuration("RISK_ENGINE_BUDGET", 2*time.Second),
+		RiskEngineAttempts:        getEnvInt("RISK_ENGINE_ATTEMPTS", 2),
+		RiskEngineMaxConcurrent:   getEnvInt("RISK_ENGINE_MAX_CONCURRENT", 16),
+		RiskEngineBreakerFailures: getEnvInt("RISK_ENGINE_BREAKER_FAILURES", 5),
+		RiskEngineBreakerOpen:     getEnvDuration("RISK_ENGINE_BREAKER_OPEN", 30*time.Second),
+		RiskEngineHalfOpenProbes:  getEnvInt("RISK_ENGINE_HALF_OPEN_PROBES", 1),
+
+		RiskCacheAmountBands: getEnvFloats("RISK_CACHE_AMOUNT_BANDS", []float64{50, 200, 1000, 5000, 10000}),
+		RiskCacheTTLs:        getEnvDurations("RISK_CACHE_TTLS"),
+
+		MetricsAddr: os.Getenv("METRICS_ADDR"),
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -47,9 +219,18 @@ func (c *Config) Validate() error {
 	if c.PostgresDSN == "" {
 		return fmt.Errorf("CRITICAL: POSTGRES_DSN is required")
 	}
+	if c.ScoreReviewThreshold <= 0 || c.ScoreReviewThreshold > c.ScoreBlockThreshold || c.ScoreBlockThreshold > 100 {
+		return fmt.Errorf("CRITICAL: SCORE_REVIEW_THRESHOLD must be in (0, SCORE_BLOCK_THRESHOLD] and SCORE_BLOCK_THRESHOLD <= 100")
+	}
+	if c.FXRefreshInterval <= 0 {
+		return fmt.Errorf("CRITICAL: FX_REFRESH_INTERVAL must be positive")
+	}
 	if c.RedisPassword == "" {
 		fmt.Println("WARNING: REDIS_PASSWORD is not set")
 	}
+	if c.AdminToken == "" {
+		fmt.Println("WARNING: ADMIN_TOKEN is not set, admin API is disabled")
+	}
 	return nil
 }
 
@@ -59,3 +240,142 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		fmt.Printf("WARNING: invalid %s=%q, using %d\n", key, value, fallback)
+		return fallback
+	}
+	return n
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		fmt.Printf("WARNING: invalid %s=%q, using %g\n", key, value, fallback)
+		return fallback
+	}
+	return f
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		fmt.Printf("WARNING: invalid %s=%q, using %t\n", key, value, fallback)
+		return fallback
+	}
+	return b
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		fmt.Printf("WARNING: invalid %s=%q, using %s\n", key, value, fallback)
+		return fallback
+	}
+	return d
+}
+
+// getEnvFloats parses a comma-separated list, e.g. "3000,10000".
+func getEnvFloats(key string, fallback []float64) []float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	var floats []float64
+	for _, item := range strings.Split(value, ",") {
+		f, err := strconv.ParseFloat(strings.TrimSpace(item), 64)
+		if err != nil {
+			fmt.Printf("WARNING: invalid number %q in %s\n", item, key)
+			continue
+		}
+		floats = append(floats, f)
+	}
+	return floats
+}
+
+// getEnvWeights parses "source=weight" pairs, e.g. "rules=1,velocity=0.3,ai=0.7".
+func getEnvWeights(key string) map[string]float64 {
+	weights := make(map[string]float64)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		w, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			fmt.Printf("WARNING: invalid weight %q in %s\n", pair, key)
+			continue
+		}
+		weights[strings.TrimSpace(name)] = w
+	}
+	return weights
+}
+
+// getEnvDurations parses "name=duration" pairs, e.g. "ALLOW=5m,BLOCK=0s".
+func getEnvDurations(key string) map[string]time.Duration {
+	durations := make(map[string]time.Duration)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			fmt.Printf("WARNING: invalid duration %q in %s\n", pair, key)
+			continue
+		}
+		durations[strings.TrimSpace(name)] = d
+	}
+	return durations
+}
+
+// getEnvPairs parses "name=value" pairs, e.g. "candidate=/app/configs/rules.v2.yaml".
+func getEnvPairs(key string) map[string]string {
+	pairs := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		pairs[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return pairs
+}
+
+// getEnvThresholds parses "tier=review:block" pairs, e.g. "high=25:55,low=50:80".
+func getEnvThresholds(key string) map[string][2]int {
+	thresholds := make(map[string][2]int)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		review, block, ok := strings.Cut(value, ":")
+		r, errR := strconv.Atoi(review)
+		b, errB := strconv.Atoi(block)
+		if !ok || errR != nil || errB != nil {
+			fmt.Printf("WARNING: invalid thresholds %q in %s\n", pair, key)
+			continue
+		}
+		thresholds[strings.TrimSpace(name)] = [2]int{r, b}
+	}
+	return thresholds
+}
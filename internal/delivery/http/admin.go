@@ -0,0 +1,584 @@
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+const maxRequestBody = 1 << 20
+
+type BanManager interface {
+	Ban(ctx context.Context, userID, actor, reason string) error
+	Unban(ctx context.Context, userID, actor, reason string) error
+	History(ctx context.Context, userID string) ([]domain.BanAudit, error)
+}
+
+type RiskManager interface {
+	ApplyLabel(ctx context.Context, userID string, fraud bool, actor, txID string) error
+	History(ctx context.Context, userID string, limit int) ([]domain.RiskScoreChange, error)
+}
+
+type MerchantManager interface {
+	Get(ctx context.Context, name string) (*domain.Merchant, error)
+	List(ctx context.Context) ([]domain.Merchant, error)
+	Upsert(ctx context.Context, m *domain.Merchant) error
+	Delete(ctx context.Context, name string) error
+}
+
+type LinkInvestigator interface {
+	Links(ctx context.Context, userID string) (*domain.LinkReport, error)
+}
+
+type ShadowReporter interface {
+	Report(ctx context.Context, window time.Duration) ([]domain.ShadowReport, error)
+}
+
+type TrustManager interface {
+	List(ctx context.Context, userID string) ([]domain.TrustedEntity, error)
+	Trust(ctx context.Context, e *domain.TrustedEntity) error
+	Untrust(ctx context.Context, userID string, ref domain.EntityRef) error
+}
+
+type AMLInvestigator interface {
+	Cases(ctx context.Context, status domain.AMLCaseStatus, limit int) ([]domain.AMLCase, error)
+}
+
+type FeatureInspector interface {
+	Definitions() []domain.FeatureDefinition
+	Version() string
+	At(ctx context.Context, userID string, at time.Time) (*domain.FeatureVector, error)
+}
+
+type CaseManager interface {
+	Get(ctx context.Context, id uint) (*domain.ReviewCase, error)
+	List(ctx context.Context, status domain.CaseStatus, assignee string, limit int) ([]domain.ReviewCase, error)
+	Assign(ctx context.Context, id uint, analyst, actor string) (*domain.ReviewCase, error)
+	Resolve(ctx context.Context, id uint, resolution domain.CaseResolution, actor, note string) (*domain.ReviewCase, error)
+}
+
+type LabelReporter interface {
+	Metrics(ctx context.Context, window time.Duration) (*domain.LabelMetrics, error)
+}
+
+type RiskCacheManager interface {
+	InvalidateUser(ctx context.Context, userID string) error
+	InvalidateMerchant(ctx context.Context, merchant string) error
+}
+
+type AdminServices struct {
+	Bans      BanManager
+	Risk      RiskManager
+	Merchants MerchantManager
+	Links     LinkInvestigator
+	Shadows   ShadowReporter
+	Trust     TrustManager
+	AML       AMLInvestigator
+	Features  FeatureInspector
+	Cases     CaseManager
+	Labels    LabelReporter
+	RiskCache RiskCacheManager
+}
+
+type AdminHandler struct {
+	bans      BanManager
+	risk      RiskManager
+	merchants MerchantManager
+	links     LinkInvestigator
+	shadows   ShadowReporter
+	trust     TrustManager
+	aml       AMLInvestigator
+	features  FeatureInspector
+	cases     CaseManager
+	labels    LabelReporter
+	riskCache RiskCacheManager
+}
+
+func NewAdminHandler(s AdminServices) *AdminHandler {
+	return &AdminHandler{
+		bans:      s.Bans,
+		risk:      s.Risk,
+		merchants: s.Merchants,
+		links:     s.Links,
+		shadows:   s.Shadows,
+		trust:     s.Trust,
+		aml:       s.AML,
+		features:  s.Features,
+		cases:     s.Cases,
+		labels:    s.Labels,
+		riskCache: s.RiskCache,
+	}
+}
+
+func RegisterAdminRoutes(mux *http.ServeMux, h *AdminHandler, token string) {
+	handle := func(pattern string, fn http.HandlerFunc) {
+		mux.Handle(pattern, requireToken(token, fn))
+	}
+
+	handle("POST /api/users/{id}/ban", h.banUser)
+	handle("POST /api/users/{id}/unban", h.unbanUser)
+	handle("GET /api/users/{id}/bans", h.banHistory)
+	handle("POST /api/users/{id}/risk-labels", h.labelRisk)
+	handle("GET /api/users/{id}/risk-history", h.riskHistory)
+	handle("GET /api/users/{id}/links", h.userLinks)
+	handle("GET /api/users/{id}/trusted", h.listTrusted)
+	handle("PUT /api/users/{id}/trusted", h.putTrusted)
+	handle("DELETE /api/users/{id}/trusted/{type}/{value}", h.deleteTrusted)
+	handle("GET /api/users/{id}/features", h.userFeatures)
+	handle("DELETE /api/users/{id}/risk-cache", h.invalidateUserRisk)
+
+	handle("GET /api/merchants", h.listMerchants)
+	handle("GET /api/merchants/{name}", h.getMerchant)
+	handle("PUT /api/merchants/{name}", h.putMerchant)
+	handle("DELETE /api/merchants/{name}", h.deleteMerchant)
+	handle("DELETE /api/merchants/{name}/risk-cache", h.invalidateMerchantRisk)
+
+	handle("GET /api/shadow/report", h.shadowReport)
+	handle("GET /api/aml/cases", h.amlCases)
+	handle("GET /api/features", h.featureDefinitions)
+	handle("GET /api/labels/metrics", h.labelMetrics)
+
+	handle("GET /api/cases", h.listCases)
+	handle("GET /api/cases/{id}", h.getCase)
+	handle("POST /api/cases/{id}/assign", h.assignCase)
+	handle("POST /api/cases/{id}/resolve", h.resolveCase)
+}
+
+type banRequest struct {
+	Actor  string `json:"actor"`
+	Reason string `json:"reason"`
+}
+
+func (h *AdminHandler) banUser(w http.ResponseWriter, r *http.Request) {
+	var req banRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := h.bans.Ban(r.Context(), r.PathValue("id"), req.Actor, req.Reason); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) unbanUser(w http.ResponseWriter, r *http.Request) {
+	var req banRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := h.bans.Unban(r.Context(), r.PathValue("id"), req.Actor, req.Reason); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) banHistory(w http.ResponseWriter, r *http.Request) {
+	audit, err := h.bans.History(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, audit)
+}
+
+type riskLabelRequest struct {
+	Actor         string `json:"actor"`
+	Label         string `json:"label"`
+	TransactionID string `json:"transaction_id"`
+}
+
+func (h *AdminHandler) labelRisk(w http.ResponseWriter, r *http.Request) {
+	var req riskLabelRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Actor == "" || (req.Label != "fraud" && req.Label != "legit") {
+		http.Error(w, `actor and label ("fraud" or "legit") are required`, http.StatusBadRequest)
+		return
+	}
+	err := h.risk.ApplyLabel(r.Context(), r.PathValue("id"), req.Label == "fraud", req.Actor, req.TransactionID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) riskHistory(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", 100)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	history, err := h.risk.History(r.Context(), r.PathValue("id"), limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+func (h *AdminHandler) userLinks(w http.ResponseWriter, r *http.Request) {
+	report, err := h.links.Links(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (h *AdminHandler) listTrusted(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.trust.List(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+type trustRequest struct {
+	EntityType  domain.EntityType `json:"entity_type"`
+	EntityValue string            `json:"entity_value"`
+	TTL         string            `json:"ttl"`
+	Actor       string            `json:"actor"`
+	Note        string            `json:"note"`
+}
+
+func (h *AdminHandler) putTrusted(w http.ResponseWriter, r *http.Request) {
+	var req trustRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	e := &domain.TrustedEntity{
+		UserID:      r.PathValue("id"),
+		EntityType:  req.EntityType,
+		EntityValue: req.EntityValue,
+		CreatedBy:   req.Actor,
+		Note:        req.Note,
+	}
+	if req.TTL != "" {
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 {
+			http.Error(w, fmt.Sprintf("invalid ttl: %q", req.TTL), http.StatusBadRequest)
+			return
+		}
+		expires := time.Now().Add(ttl)
+		e.ExpiresAt = &expires
+	}
+	if err := h.trust.Trust(r.Context(), e); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, e)
+}
+
+func (h *AdminHandler) deleteTrusted(w http.ResponseWriter, r *http.Request) {
+	ref := domain.EntityRef{Type: domain.EntityType(r.PathValue("type")), Value: r.PathValue("value")}
+	if err := h.trust.Untrust(r.Context(), r.PathValue("id"), ref); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) listMerchants(w http.ResponseWriter, r *http.Request) {
+	merchants, err := h.merchants.List(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, merchants)
+}
+
+func (h *AdminHandler) getMerchant(w http.ResponseWriter, r *http.Request) {
+	m, err := h.merchants.Get(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+type merchantRequest struct {
+	Category  string                  `json:"category"`
+	MCC       string                  `json:"mcc"`
+	RiskTier  domain.MerchantRiskTier `json:"risk_tier"`
+	Country   string                  `json:"country"`
+	IsBlocked bool                    `json:"is_blocked"`
+	Actor     string                  `json:"actor"`
+}
+
+func (h *AdminHandler) putMerchant(w http.ResponseWriter, r *http.Request) {
+	var req merchantRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	m := &domain.Merchant{
+		Name:      r.PathValue("name"),
+		Category:  req.Category,
+		MCC:       req.MCC,
+		RiskTier:  req.RiskTier,
+		Country:   req.Country,
+		IsBlocked: req.IsBlocked,
+		UpdatedBy: req.Actor,
+	}
+	if err := h.merchants.Upsert(r.Context(), m); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+func (h *AdminHandler) deleteMerchant(w http.ResponseWriter, r *http.Request) {
+	if err := h.merchants.Delete(r.Context(), r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) invalidateUserRisk(w http.ResponseWriter, r *http.Request) {
+	if err := h.riskCache.InvalidateUser(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) invalidateMerchantRisk(w http.ResponseWriter, r *http.Request) {
+	if err := h.riskCache.InvalidateMerchant(r.Context(), r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) shadowReport(w http.ResponseWriter, r *http.Request) {
+	window, err := queryDuration(r, "window", 24*time.Hour)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reports, err := h.shadows.Report(r.Context(), window)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, reports)
+}
+
+func (h *AdminHandler) amlCases(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", 100)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cases, err := h.aml.Cases(r.Context(), domain.AMLCaseStatus(r.URL.Query().Get("status")), limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, cases)
+}
+
+func (h *AdminHandler) userFeatures(w http.ResponseWriter, r *http.Request) {
+	at := time.Now()
+	if value := r.URL.Query().Get("at"); value != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, value); err != nil {
+			http.Error(w, fmt.Sprintf("invalid at: %q", value), http.StatusBadRequest)
+			return
+		}
+	}
+	vector, err := h.features.At(r.Context(), r.PathValue("id"), at)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, vector)
+}
+
+type featureDefinitionsResponse struct {
+	Version     string                     `json:"version"`
+	Definitions []domain.FeatureDefinition `json:"definitions"`
+}
+
+func (h *AdminHandler) featureDefinitions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, featureDefinitionsResponse{
+		Version:     h.features.Version(),
+		Definitions: h.features.Definitions(),
+	})
+}
+
+func (h *AdminHandler) labelMetrics(w http.ResponseWriter, r *http.Request) {
+	window, err := queryDuration(r, "window", 30*24*time.Hour)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	metrics, err := h.labels.Metrics(r.Context(), window)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, metrics)
+}
+
+func (h *AdminHandler) listCases(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", 100)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	cases, err := h.cases.List(r.Context(), domain.CaseStatus(q.Get("status")), q.Get("assignee"), limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, cases)
+}
+
+func (h *AdminHandler) getCase(w http.ResponseWriter, r *http.Request) {
+	id, ok := caseID(w, r)
+	if !ok {
+		return
+	}
+	c, err := h.cases.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+type assignCaseRequest struct {
+	Analyst string `json:"analyst"`
+	Actor   string `json:"actor"`
+}
+
+func (h *AdminHandler) assignCase(w http.ResponseWriter, r *http.Request) {
+	id, ok := caseID(w, r)
+	if !ok {
+		return
+	}
+	var req assignCaseRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	c, err := h.cases.Assign(r.Context(), id, req.Analyst, req.Actor)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+type resolveCaseRequest struct {
+	Resolution domain.CaseResolution `json:"resolution"`
+	Actor      string                `json:"actor"`
+	Note       string                `json:"note"`
+}
+
+func (h *AdminHandler) resolveCase(w http.ResponseWriter, r *http.Request) {
+	id, ok := caseID(w, r)
+	if !ok {
+		return
+	}
+	var req resolveCaseRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	c, err := h.cases.Resolve(r.Context(), id, req.Resolution, req.Actor, req.Note)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+func caseID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	value := r.PathValue("id")
+	id, err := strconv.ParseUint(value, 10, 0)
+	if err != nil || id == 0 {
+		http.Error(w, fmt.Sprintf("invalid case id: %q", value), http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func queryInt(r *http.Request, key string, fallback int) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s: %q", key, value)
+	}
+	return n, nil
+}
+
+func queryDuration(r *http.Request, key string, fallback time.Duration) (time.Duration, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s: %q", key, value)
+	}
+	return d, nil
+}
+
+// requireToken rejects every request when no token is configured.
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("Failed to write JSON response", "err", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, domain.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		slog.Error("Admin request failed", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
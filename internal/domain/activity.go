@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Activity is a processed transaction as remembered by the velocity windows.
+type Activity struct {
+	TransactionID string    `json:"id"`
+	Amount        float64   `json:"amount"`
+	Merchant      string    `json:"merchant"`
+	Location      string    `json:"location"`
+	IP            string    `json:"ip,omitempty"`
+	At            time.Time `json:"at"`
+}
+
+// ActivityStats aggregates a user's activity within one window.
+type ActivityStats struct {
+	Count     int
+	AmountSum float64
+	Locations int
+}
+
+// SummarizeActivity aggregates the activity in (since, until].
+func SummarizeActivity(activity []Activity, since, until time.Time) ActivityStats {
+	var s ActivityStats
+	locations := make(map[string]struct{})
+	for _, a := range activity {
+		if !a.At.After(since) || a.At.After(until) {
+			continue
+		}
+		s.Count++
+		s.AmountSum += a.Amount
+		locations[strings.ToLower(a.Location)] = struct{}{}
+	}
+	s.Locations = len(locations)
+	return s
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+type AMLTypology string
+
+const AMLTypologyStructuring AMLTypology = "structuring"
+
+type AMLCaseStatus string
+
+const (
+	AMLCaseOpen     AMLCaseStatus = "open"
+	AMLCaseReported AMLCaseStatus = "reported"
+	AMLCaseClosed   AMLCaseStatus = "closed"
+)
+
+// AMLCase is raised for compliance review, separately from the fraud
+// decision on the transaction that triggered it.
+type AMLCase struct {
+	ID               uint          `gorm:"primaryKey" json:"id"`
+	TransactionID    string        `gorm:"uniqueIndex;not null;size:100" json:"transaction_id"`
+	UserID           string        `gorm:"index;not null;size:100" json:"user_id"`
+	Typology         AMLTypology   `gorm:"size:30;not null" json:"typology"`
+	Threshold        float64       `gorm:"type:decimal(18,2)" json:"threshold"`
+	Currency         string        `gorm:"size:10" json:"currency"`
+	TransactionCount int           `json:"transaction_count"`
+	TotalAmount      float64       `gorm:"type:decimal(18,2)" json:"total_amount"`
+	TransactionIDs   []string      `gorm:"serializer:json;type:jsonb" json:"transaction_ids"`
+	LinkedUsers      []string      `gorm:"serializer:json;type:jsonb" json:"linked_users,omitempty"`
+	PeriodStart      time.Time     `json:"period_start"`
+	Status           AMLCaseStatus `gorm:"size:20;index;default:'open'" json:"status"`
+	CreatedAt        time.Time     `gorm:"autoCreateTime;index" json:"created_at"`
+}
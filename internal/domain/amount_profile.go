@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+const histogramBase = 1.25
+
+// AmountProfile is a per-user running summary of transaction amounts.
+// Mean and variance are maintained with Welford's algorithm; percentiles are
+// approximated from a sparse log-scale histogram, so the profile stays small
+// regardless of how many transactions it has seen.
+type AmountProfile struct {
+	Count     int64         `json:"count"`
+	Mean      float64       `json:"mean"`
+	M2        float64       `json:"m2"`
+	Max       float64       `json:"max"`
+	Histogram map[int]int64 `json:"hist"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+func NewAmountProfile(amounts ...float64) *AmountProfile {
+	p := &AmountProfile{}
+	for _, a := range amounts {
+		p.Add(a)
+	}
+	return p
+}
+
+func (p *AmountProfile) Add(amount float64) {
+	if amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return
+	}
+	p.Count++
+	delta := amount - p.Mean
+	p.Mean += delta / float64(p.Count)
+	p.M2 += delta * (amount - p.Mean)
+	if amount > p.Max {
+		p.Max = amount
+	}
+
+	if p.Histogram == nil {
+		p.Histogram = make(map[int]int64)
+	}
+	p.Histogram[bucketOf(amount)]++
+	p.UpdatedAt = time.Now()
+}
+
+func (p *AmountProfile) Variance() float64 {
+	if p.Count < 2 {
+		return 0
+	}
+	return p.M2 / float64(p.Count-1)
+}
+
+func (p *AmountProfile) StdDev() float64 {
+	return math.Sqrt(p.Variance())
+}
+
+// ZScore measures how far amount is from the mean. The deviation is floored
+// at 5% of the mean so users with near-constant amounts don't produce
+// infinite scores on the first small change.
+func (p *AmountProfile) ZScore(amount float64) float64 {
+	if p.Count == 0 {
+		return 0
+	}
+	std := math.Max(p.StdDev(), math.Max(p.Mean*0.05, 1))
+	return (amount - p.Mean) / std
+}
+
+// Percentile returns the approximate amount below which q (0..1) of the
+// observed amounts fall.
+func (p *AmountProfile) Percentile(q float64) float64 {
+	if p.Count == 0 {
+		return 0
+	}
+	target := int64(math.Ceil(q * float64(p.Count)))
+	var seen int64
+	for _, b := range slices.Sorted(maps.Keys(p.Histogram)) {
+		seen += p.Histogram[b]
+		if seen >= target {
+			return math.Min(bucketUpper(b), p.Max)
+		}
+	}
+	return p.Max
+}
+
+// Rank returns the approximate share (0..1) of observed amounts that are
+// lower than amount.
+func (p *AmountProfile) Rank(amount float64) float64 {
+	if p.Count == 0 {
+		return 0
+	}
+	if amount > p.Max {
+		return 1
+	}
+	target := bucketOf(amount)
+	var below float64
+	for b, n := range p.Histogram {
+		switch {
+		case b < target:
+			below += float64(n)
+		case b == target:
+			below += float64(n) / 2
+		}
+	}
+	return below / float64(p.Count)
+}
+
+func bucketOf(amount float64) int {
+	if amount < 1 {
+		return 0
+	}
+	return int(math.Floor(math.Log(amount)/math.Log(histogramBase))) + 1
+}
+
+func bucketUpper(b int) float64 {
+	if b == 0 {
+		return 1
+	}
+	return math.Pow(histogramBase, float64(b))
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAmountProfile_WelfordMatchesBatchStatistics(t *testing.T) {
+	amounts := []float64{120, 80, 400, 95, 310, 150, 60, 220}
+	p := NewAmountProfile(amounts...)
+
+	var sum float64
+	for _, a := range amounts {
+		sum += a
+	}
+	mean := sum / float64(len(amounts))
+
+	var sq float64
+	for _, a := range amounts {
+		sq += (a - mean) * (a - mean)
+	}
+	variance := sq / float64(len(amounts)-1)
+
+	if math.Abs(p.Mean-mean) > 1e-9 {
+		t.Errorf("Expected mean %f, got %f", mean, p.Mean)
+	}
+	if math.Abs(p.Variance()-variance) > 1e-6 {
+		t.Errorf("Expected variance %f, got %f", variance, p.Variance())
+	}
+	if p.Max != 400 {
+		t.Errorf("Expected max 400, got %f", p.Max)
+	}
+}
+
+func TestAmountProfile_PercentilesAndRank(t *testing.T) {
+	p := NewAmountProfile()
+	for i := 1; i <= 1000; i++ {
+		p.Add(float64(i))
+	}
+
+	p50 := p.Percentile(0.5)
+	if p50 < 400 || p50 > 650 {
+		t.Errorf("Expected p50 near 500, got %f", p50)
+	}
+	if p99 := p.Percentile(0.99); p99 < 900 || p99 > 1000 {
+		t.Errorf("Expected p99 near 990, got %f", p99)
+	}
+	if r := p.Rank(5000); r != 1 {
+		t.Errorf("Expected rank 1 above max, got %f", r)
+	}
+	if r := p.Rank(10); r > 0.05 {
+		t.Errorf("Expected low rank for small amount, got %f", r)
+	}
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+type BanAction string
+
+const (
+	BanActionBan   BanAction = "ban"
+	BanActionUnban BanAction = "unban"
+)
+
+type BanAudit struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    string    `gorm:"index;not null;size:100" json:"user_id"`
+	Action    BanAction `gorm:"size:10;not null" json:"action"`
+	Actor     string    `gorm:"size:100;not null" json:"actor"`
+	Reason    string    `gorm:"type:text" json:"reason"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
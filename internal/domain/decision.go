@@ -0,0 +1,39 @@
+package domain
+
+type Decision string
+
+const (
+	DecisionAllow  Decision = "ALLOW"
+	DecisionReview Decision = "REVIEW"
+	DecisionBlock  Decision = "BLOCK"
+)
+
+func (d Decision) severity() int {
+	switch d {
+	case DecisionBlock:
+		return 2
+	case DecisionReview:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (d Decision) AtLeast(other Decision) bool {
+	return d.severity() >= other.severity()
+}
+
+func MaxDecision(a, b Decision) Decision {
+	if a.AtLeast(b) {
+		return a
+	}
+	return b
+}
+
+func ParseDecision(s string) (Decision, bool) {
+	switch Decision(s) {
+	case DecisionAllow, DecisionReview, DecisionBlock:
+		return Decision(s), true
+	}
+	return "", false
+}
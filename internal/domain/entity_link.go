@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+type EntityType string
+
+const (
+	EntityIP       EntityType = "ip"
+	EntityDevice   EntityType = "device"
+	EntityMerchant EntityType = "merchant"
+	EntityLocation EntityType = "location"
+	EntityCountry  EntityType = "country"
+)
+
+type EntityRef struct {
+	Type  EntityType `json:"type"`
+	Value string     `json:"value"`
+}
+
+// EntityLink is one edge of the user <-> entity graph built from processed
+// transactions.
+type EntityLink struct {
+	ID          uint       `gorm:"primaryKey" json:"-"`
+	UserID      string     `gorm:"size:100;not null;uniqueIndex:idx_entity_link" json:"user_id"`
+	EntityType  EntityType `gorm:"size:20;not null;uniqueIndex:idx_entity_link;index:idx_entity_lookup" json:"entity_type"`
+	EntityValue string     `gorm:"size:255;not null;uniqueIndex:idx_entity_link;index:idx_entity_lookup" json:"entity_value"`
+	TxCount     int64      `gorm:"not null;default:1" json:"tx_count"`
+	FirstSeen   time.Time  `json:"first_seen"`
+	LastSeen    time.Time  `gorm:"index" json:"last_seen"`
+}
+
+func (l EntityLink) Ref() EntityRef {
+	return EntityRef{Type: l.EntityType, Value: l.EntityValue}
+}
+
+type LinkedUser struct {
+	UserID string      `json:"user_id"`
+	Depth  int         `json:"depth"`
+	Shared []EntityRef `json:"shared"`
+}
+
+// LinkReport is a user's neighbourhood in the entity graph: their own
+// entities and every user reachable through shared IPs or devices.
+type LinkReport struct {
+	UserID    string       `json:"user_id"`
+	Entities  []EntityLink `json:"entities"`
+	Linked    []LinkedUser `json:"linked_users"`
+	Truncated bool         `json:"truncated,omitempty"`
+}
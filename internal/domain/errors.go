@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrInvalidInput = errors.New("invalid input")
+)
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// FeatureDefinition names a group of facts published by one producer. The
+// version is bumped whenever their computation changes, so vectors built
+// under different definitions are never mixed in training or comparisons.
+type FeatureDefinition struct {
+	Name        string   `json:"name"`
+	Version     int      `json:"version"`
+	Prefixes    []string `json:"prefixes"`
+	Description string   `json:"description"`
+}
+
+// FeatureVector is the numeric view of the features a decision was made on,
+// keyed by fact name. Version identifies the feature definitions used.
+type FeatureVector struct {
+	TransactionID string             `json:"transaction_id"`
+	Version       string             `json:"version"`
+	Values        map[string]float64 `json:"values"`
+	At            time.Time          `json:"at"`
+}
+
+// FeatureSnapshot is the feature vector logged for every decision, for
+// point-in-time lookups, backtests and model training.
+type FeatureSnapshot struct {
+	ID            uint               `gorm:"primaryKey" json:"id"`
+	TransactionID string             `gorm:"uniqueIndex;not null;size:100" json:"transaction_id"`
+	UserID        string             `gorm:"index:idx_feature_snapshot_user_at;not null;size:100" json:"user_id"`
+	Version       string             `gorm:"index;not null;size:50" json:"version"`
+	Values        map[string]float64 `gorm:"serializer:json;type:jsonb" json:"values"`
+	Decision      Decision           `gorm:"size:10" json:"decision"`
+	RiskScore     int                `json:"risk_score"`
+	At            time.Time          `gorm:"index:idx_feature_snapshot_user_at" json:"at"`
+	CreatedAt     time.Time          `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (s FeatureSnapshot) Vector() FeatureVector {
+	return FeatureVector{TransactionID: s.TransactionID, Version: s.Version, Values: s.Values, At: s.At}
+}
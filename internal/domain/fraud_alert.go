@@ -1,11 +1,21 @@
 package domain
 
 type FraudAlert struct {
-	TransactionID string  `json:"transaction_id"`
-	Reason        string  `json:"reason"`
-	AIPushMessage string  `json:"ai_push_msg"`
-	IsBlocked     bool    `json:"is_blocked"`
-	Amount        float64 `json:"amount"`
-	Location      string  `json:"location"`
-	Merchant      string  `json:"merchant"`
+	TransactionID    string   `json:"transaction_id"`
+	Reason           string   `json:"reason"`
+	Reasons          Reasons  `json:"reasons,omitempty"`
+	AIPushMessage    string   `json:"ai_push_msg"`
+	IsBlocked        bool     `json:"is_blocked"`
+	Decision         Decision `json:"decision,omitempty"`
+	RiskScore        int      `json:"risk_score"`
+	Amount           float64  `json:"amount"`
+	Currency         string   `json:"currency,omitempty"`
+	OriginalAmount   float64  `json:"original_amount,omitempty"`
+	OriginalCurrency string   `json:"original_currency,omitempty"`
+	Location         string   `json:"location"`
+	Merchant         string   `json:"merchant"`
+	IP               string   `json:"ip,omitempty"`
+	IPFlags          []string `json:"ip_flags,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	Degraded         bool     `json:"degraded,omitempty"`
 }
@@ -3,14 +3,30 @@ package domain
 import "time"
 
 type FraudEvent struct {
-	ID            uint    `gorm:"primaryKey"`
-	TransactionID string  `gorm:"uniqueIndex;not null;size:100"`
-	UserID        string  `gorm:"index;not null;size:100"`
-	Merchant      string  `gorm:"size:255"`
-	Amount        float64 `gorm:"type:decimal(10,2)"`
-	Location      string  `gorm:"size:255"`
-	IsBlocked     bool
-	AIReason      string    `gorm:"type:text"`
-	AIPushMsg     string    `gorm:"type:text"`
-	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	ID               uint     `gorm:"primaryKey"`
+	TransactionID    string   `gorm:"uniqueIndex;not null;size:100"`
+	UserID           string   `gorm:"index;not null;size:100"`
+	Merchant         string   `gorm:"size:255"`
+	Amount           float64  `gorm:"type:decimal(18,2)"`
+	Currency         string   `gorm:"size:10"`
+	OriginalAmount   float64  `gorm:"type:decimal(18,2)"`
+	OriginalCurrency string   `gorm:"size:10"`
+	Location         string   `gorm:"size:255"`
+	IP               string   `gorm:"size:45;index"`
+	DeviceID         string   `gorm:"size:100;default:''"`
+	IPFlags          []string `gorm:"serializer:json;type:jsonb"`
+	IsBlocked        bool
+	Decision         Decision     `gorm:"size:10;index;default:'ALLOW'"`
+	RiskScore        int          `gorm:"default:0"`
+	AIBlocked        bool         `gorm:"default:false"`
+	AIRiskScore      int          `gorm:"default:0"`
+	AIReason         string       `gorm:"type:text"`
+	Degraded         bool         `gorm:"default:false;index"`
+	Reasons          Reasons      `gorm:"serializer:json;type:jsonb"`
+	AIPushMsg        string       `gorm:"type:text"`
+	Tags             []string     `gorm:"serializer:json;type:jsonb"`
+	Label            LabelOutcome `gorm:"size:10;index;default:''"`
+	LabelSource      string       `gorm:"size:50"`
+	LabeledAt        *time.Time
+	CreatedAt        time.Time `gorm:"autoCreateTime"`
 }
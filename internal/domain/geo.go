@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+const earthRadiusKm = 6371.0
+
+type GeoPoint struct {
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+	Country string  `json:"country,omitempty"`
+}
+
+type GeoPosition struct {
+	GeoPoint
+	Location string    `json:"location"`
+	At       time.Time `json:"at"`
+}
+
+func (p GeoPoint) DistanceKm(other GeoPoint) float64 {
+	lat1, lat2 := p.Lat*math.Pi/180, other.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (other.Lon - p.Lon) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,17 @@
+package domain
+
+const (
+	IPFlagAllowed = "allowlisted"
+	IPFlagDenied  = "denylisted"
+	IPFlagProxy   = "proxy"
+	IPFlagTor     = "tor"
+	IPFlagShared  = "shared"
+	IPFlagInvalid = "invalid"
+)
+
+type IPReputation struct {
+	Allowed bool
+	Denied  bool
+	Proxy   bool
+	Tor     bool
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+type LabelOutcome string
+
+const (
+	// LabelFraud confirms the transaction was fraud, e.g. a chargeback.
+	LabelFraud LabelOutcome = "fraud"
+	// LabelLegit marks the transaction as genuine, e.g. a disputed block
+	// that turned out to be a false positive.
+	LabelLegit LabelOutcome = "legit"
+)
+
+// FraudLabel is ground truth about a decided transaction that arrives after
+// the fact, from the labels topic or an NDJSON import.
+type FraudLabel struct {
+	TransactionID string       `json:"transaction_id"`
+	Label         LabelOutcome `json:"label"`
+	Source        string       `json:"source"`
+	Reason        string       `json:"reason,omitempty"`
+	ReportedAt    time.Time    `json:"reported_at"`
+}
+
+// LabelMetrics measures the decisions taken within a window against their
+// labels. BLOCK and REVIEW count as flagging the transaction as fraud.
+type LabelMetrics struct {
+	Window         string  `json:"window"`
+	Decisions      int64   `json:"decisions"`
+	Labeled        int64   `json:"labeled"`
+	Fraud          int64   `json:"fraud"`
+	Legit          int64   `json:"legit"`
+	TruePositives  int64   `json:"true_positives"`
+	FalsePositives int64   `json:"false_positives"`
+	FalseNegatives int64   `json:"false_negatives"`
+	TrueNegatives  int64   `json:"true_negatives"`
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+type MerchantRiskTier string
+
+const (
+	MerchantRiskLow    MerchantRiskTier = "low"
+	MerchantRiskMedium MerchantRiskTier = "medium"
+	MerchantRiskHigh   MerchantRiskTier = "high"
+)
+
+func (t MerchantRiskTier) Valid() bool {
+	switch t {
+	case MerchantRiskLow, MerchantRiskMedium, MerchantRiskHigh:
+		return true
+	}
+	return false
+}
+
+type Merchant struct {
+	Name      string           `gorm:"primaryKey;size:255" json:"name"`
+	Category  string           `gorm:"size:100" json:"category"`
+	MCC       string           `gorm:"size:4;index" json:"mcc"`
+	RiskTier  MerchantRiskTier `gorm:"size:10;not null;default:'medium'" json:"risk_tier"`
+	Country   string           `gorm:"size:100" json:"country"`
+	IsBlocked bool             `gorm:"default:false" json:"is_blocked"`
+	UpdatedBy string           `gorm:"size:100" json:"updated_by,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
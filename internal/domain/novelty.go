@@ -0,0 +1,21 @@
+package domain
+
+import "fmt"
+
+// Novelty describes which parts of a transaction the user has never paid
+// with before. DaysSinceFirstSeen is the age of the user's history; zero for
+// a user seen for the first time.
+type Novelty struct {
+	NewMerchant        bool
+	NewLocation        bool
+	NewCountry         bool
+	NewIP              bool
+	DaysSinceFirstSeen int
+}
+
+func (n Novelty) String() string {
+	return fmt.Sprintf(
+		"new_merchant:%t,new_location:%t,new_country:%t,new_ip:%t,days_since_first_seen:%d",
+		n.NewMerchant, n.NewLocation, n.NewCountry, n.NewIP, n.DaysSinceFirstSeen,
+	)
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReasonSource names what produced a reason. Detector findings use the
+// detector's name as their source.
+type ReasonSource string
+
+const (
+	ReasonSourceRule     ReasonSource = "rule"
+	ReasonSourceVelocity ReasonSource = "velocity"
+	ReasonSourceAI       ReasonSource = "ai"
+	ReasonSourceCache    ReasonSource = "cache"
+	ReasonSourceBan      ReasonSource = "ban"
+	ReasonSourceDegraded ReasonSource = "degraded"
+)
+
+// Reason is one explainable contribution to a decision. Score is the number
+// of points it added to the weighted risk score.
+type Reason struct {
+	Code    string       `json:"code"`
+	Source  ReasonSource `json:"source"`
+	Name    string       `json:"name,omitempty"`
+	Score   int          `json:"score"`
+	Message string       `json:"message"`
+}
+
+func (r Reason) String() string {
+	switch r.Source {
+	case ReasonSourceAI, ReasonSourceCache:
+		return r.Message
+	case ReasonSourceRule:
+		if r.Score > 0 {
+			return fmt.Sprintf("[%s] %s (rule %s, +%d)", r.Name, r.Message, r.Code, r.Score)
+		}
+		return fmt.Sprintf("[%s] %s (rule %s)", r.Name, r.Message, r.Code)
+	}
+	return fmt.Sprintf("[%s] %s", r.Code, r.Message)
+}
+
+type Reasons []Reason
+
+// Summary renders the reasons as the single human-readable line carried in
+// FraudAlert.Reason.
+func (rs Reasons) Summary(decision Decision) string {
+	parts := make([]string, len(rs))
+	for i, r := range rs {
+		parts[i] = r.String()
+	}
+	summary := strings.Join(parts, " + ")
+	if decision == DecisionReview {
+		summary = "[PENDING REVIEW] " + summary
+	}
+	return summary
+}
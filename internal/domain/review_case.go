@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+type CaseStatus string
+
+const (
+	CaseOpen          CaseStatus = "open"
+	CaseInvestigating CaseStatus = "investigating"
+	CaseResolved      CaseStatus = "resolved"
+)
+
+type CaseResolution string
+
+const (
+	CaseResolutionFraud CaseResolution = "fraud"
+	CaseResolutionLegit CaseResolution = "legit"
+)
+
+// ReviewCase is a flagged transaction in the manual review queue. It is also
+// the message published when a case is resolved.
+type ReviewCase struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	TransactionID  string         `gorm:"uniqueIndex;not null;size:100" json:"transaction_id"`
+	UserID         string         `gorm:"index;not null;size:100" json:"user_id"`
+	Decision       Decision       `gorm:"size:10" json:"decision"`
+	RiskScore      int            `json:"risk_score"`
+	Amount         float64        `gorm:"type:decimal(18,2)" json:"amount"`
+	Currency       string         `gorm:"size:10" json:"currency"`
+	Merchant       string         `gorm:"size:255" json:"merchant"`
+	Reason         string         `gorm:"type:text" json:"reason"`
+	Status         CaseStatus     `gorm:"size:20;index;not null;default:'open'" json:"status"`
+	AssignedTo     string         `gorm:"size:100;index" json:"assigned_to,omitempty"`
+	Resolution     CaseResolution `gorm:"size:10" json:"resolution,omitempty"`
+	ResolutionNote string         `gorm:"type:text" json:"resolution_note,omitempty"`
+	ResolvedBy     string         `gorm:"size:100" json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time     `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
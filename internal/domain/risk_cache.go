@@ -0,0 +1,11 @@
+package domain
+
+// RiskCacheKey is the context an AI verdict was given in. A cached verdict
+// is only reused for a transaction with the same key, until the user or the
+// merchant is invalidated.
+type RiskCacheKey struct {
+	UserID     string
+	Merchant   string
+	AmountBand int
+	Location   string
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+type RiskCause string
+
+const (
+	RiskCauseBlock  RiskCause = "block"
+	RiskCauseReview RiskCause = "review"
+	RiskCauseClean  RiskCause = "clean"
+	RiskCauseDecay  RiskCause = "decay"
+	RiskCauseFraud  RiskCause = "label_fraud"
+	RiskCauseLegit  RiskCause = "label_legit"
+)
+
+type RiskScoreChange struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        string    `gorm:"index;not null;size:100" json:"user_id"`
+	OldScore      int       `json:"old_score"`
+	NewScore      int       `json:"new_score"`
+	Cause         RiskCause `gorm:"size:20;not null" json:"cause"`
+	Actor         string    `gorm:"size:100" json:"actor,omitempty"`
+	TransactionID string    `gorm:"size:100" json:"transaction_id,omitempty"`
+	CreatedAt     time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
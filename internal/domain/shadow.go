@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// ShadowDecision is what a shadow evaluator would have decided for a
+// transaction, next to the live decision that was actually published.
+type ShadowDecision struct {
+	ID             uint      `gorm:"primaryKey" json:"-"`
+	Shadow         string    `gorm:"size:100;not null;index" json:"shadow"`
+	TransactionID  string    `gorm:"size:100;not null;index" json:"transaction_id"`
+	UserID         string    `gorm:"size:100;not null" json:"user_id"`
+	LiveDecision   Decision  `gorm:"size:10;not null" json:"live_decision"`
+	LiveScore      int       `json:"live_score"`
+	ShadowDecision Decision  `gorm:"size:10;not null" json:"shadow_decision"`
+	ShadowScore    int       `json:"shadow_score"`
+	Reason         string    `gorm:"type:text" json:"reason"`
+	CreatedAt      time.Time `gorm:"index" json:"created_at"`
+}
+
+type ShadowReport struct {
+	Shadow        string  `json:"shadow"`
+	Total         int64   `json:"total"`
+	Agreements    int64   `json:"agreements"`
+	AgreementRate float64 `json:"agreement_rate"`
+	ExtraBlocks   int64   `json:"extra_blocks"`
+	MissedBlocks  int64   `json:"missed_blocks"`
+	ExtraReviews  int64   `json:"extra_reviews"`
+	MissedReviews int64   `json:"missed_reviews"`
+	AvgScoreDelta float64 `json:"avg_score_delta"`
+}
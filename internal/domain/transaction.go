@@ -10,5 +10,6 @@ type Transaction struct {
 	Merchant  string    `json:"merchant"`
 	Location  string    `json:"location"`
 	IP        string    `json:"ip"`
+	DeviceID  string    `json:"device_id,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
 }
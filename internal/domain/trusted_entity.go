@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+type TrustSource string
+
+const (
+	TrustManual  TrustSource = "manual"
+	TrustLearned TrustSource = "learned"
+)
+
+// TrustedEntity is a merchant, location or IP on a user's allowlist. Manual
+// entries are trusted as soon as they are added; learned entries only once
+// enough clean transactions have been seen. Either kind stops counting after
+// ExpiresAt.
+type TrustedEntity struct {
+	ID          uint        `gorm:"primaryKey" json:"-"`
+	UserID      string      `gorm:"size:100;not null;uniqueIndex:idx_trusted_entity" json:"user_id"`
+	EntityType  EntityType  `gorm:"size:20;not null;uniqueIndex:idx_trusted_entity" json:"entity_type"`
+	EntityValue string      `gorm:"size:255;not null;uniqueIndex:idx_trusted_entity" json:"entity_value"`
+	Source      TrustSource `gorm:"size:10;not null" json:"source"`
+	CleanCount  int64       `gorm:"not null;default:0" json:"clean_count"`
+	LastSeen    *time.Time  `json:"last_seen,omitempty"`
+	ExpiresAt   *time.Time  `gorm:"index" json:"expires_at,omitempty"`
+	CreatedBy   string      `gorm:"size:100" json:"created_by,omitempty"`
+	Note        string      `gorm:"type:text" json:"note,omitempty"`
+	Active      bool        `gorm:"-" json:"active"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+func (e TrustedEntity) Ref() EntityRef {
+	return EntityRef{Type: e.EntityType, Value: e.EntityValue}
+}
+
+// Trusted reports whether the entry counts at now, given the number of clean
+// transactions a learned entry needs.
+func (e TrustedEntity) Trusted(now time.Time, minClean int64) bool {
+	if e.ExpiresAt != nil && !now.Before(*e.ExpiresAt) {
+		return false
+	}
+	return e.Source == TrustManual || e.CleanCount >= minClean
+}
@@ -3,13 +3,19 @@ package domain
 import "time"
 
 type User struct {
-	ID        string       `gorm:"primaryKey"`
-	Email     string       `gorm:"size:255;default:'unknown@mail.com'"`
-	RiskScore int          `gorm:"default:10"`
-	IsBanned  bool         `gorm:"default:false"`
-	MaxTx     float64      `gorm:"-"`
-	AvgTx     float64      `gorm:"-"`
-	Events    []FraudEvent `gorm:"foreignKey:UserID"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID            string `gorm:"primaryKey"`
+	Email         string `gorm:"size:255;default:'unknown@mail.com'"`
+	RiskScore     int    `gorm:"default:10"`
+	RiskBaseline  int    `gorm:"default:10"`
+	RiskUpdatedAt *time.Time
+	IsBanned      bool `gorm:"default:false"`
+	BannedAt      *time.Time
+	BannedBy      string       `gorm:"size:100"`
+	BanReason     string       `gorm:"type:text"`
+	MaxTx         float64      `gorm:"-"`
+	AvgTx         float64      `gorm:"-"`
+	Novelty       *Novelty     `gorm:"-"`
+	Events        []FraudEvent `gorm:"foreignKey:UserID"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
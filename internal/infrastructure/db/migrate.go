@@ -0,0 +1,39 @@
+package db
+
+import (
+	"github.com/tokyosplif/fraud-core/internal/domain"
+	"gorm.io/gorm"
+)
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(
+		&domain.User{},
+		&domain.FraudEvent{},
+		&domain.BanAudit{},
+		&domain.RiskScoreChange{},
+		&domain.Merchant{},
+		&domain.EntityLink{},
+		&domain.ShadowDecision{},
+		&domain.TrustedEntity{},
+		&domain.AMLCase{},
+		&domain.FeatureSnapshot{},
+		&domain.ReviewCase{},
+	); err != nil {
+		return err
+	}
+
+	// Events stored before FX normalization only carried the raw amount.
+	if err := db.Exec(`UPDATE fraud_events
+		SET original_amount = amount,
+			currency = COALESCE(currency, ''),
+			original_currency = COALESCE(original_currency, '')
+		WHERE original_amount IS NULL`).Error; err != nil {
+		return err
+	}
+
+	// Users created before adaptive risk decay towards their own score, not
+	// the column default.
+	return db.Exec(`UPDATE users
+		SET risk_baseline = risk_score
+		WHERE risk_updated_at IS NULL`).Error
+}
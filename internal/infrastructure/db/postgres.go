@@ -3,9 +3,12 @@ package db
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/tokyosplif/fraud-core/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PostgresRepository struct {
@@ -33,15 +36,426 @@ func (r *PostgresRepository) SaveFraudEvent(ctx context.Context, event *domain.F
 	return r.db.WithContext(ctx).Create(event).Error
 }
 
-func (r *PostgresRepository) GetUserStats(ctx context.Context, userID string) (float64, float64, error) {
-	var stats struct {
-		MaxAmount float64 `gorm:"column:max_amount"`
-		AvgAmount float64 `gorm:"column:avg_amount"`
+func (r *PostgresRepository) ListUsers(ctx context.Context) ([]domain.User, error) {
+	var users []domain.User
+	err := r.db.WithContext(ctx).Order("id").Find(&users).Error
+	return users, err
+}
+
+// ListFraudEvents returns events created in [since, until), oldest first.
+// A zero limit returns all of them.
+func (r *PostgresRepository) ListFraudEvents(ctx context.Context, since, until time.Time, limit int) ([]domain.FraudEvent, error) {
+	q := r.db.WithContext(ctx).
+		Where("created_at >= ? AND created_at < ?", since, until).
+		Order("created_at, id")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	var events []domain.FraudEvent
+	err := q.Find(&events).Error
+	return events, err
+}
+
+func (r *PostgresRepository) GetUserAmounts(ctx context.Context, userID string, limit int) ([]float64, error) {
+	var amounts []float64
+	err := r.db.WithContext(ctx).
+		Model(&domain.FraudEvent{}).
+		Where("user_id = ? AND is_blocked = ?", userID, false).
+		Order("created_at DESC").
+		Limit(limit).
+		Pluck("amount", &amounts).Error
+	return amounts, err
+}
+
+func (r *PostgresRepository) SetUserBan(ctx context.Context, userID string, banned bool, actor, reason string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates := map[string]any{
+			"is_banned":  banned,
+			"banned_at":  nil,
+			"banned_by":  "",
+			"ban_reason": "",
+		}
+		action := domain.BanActionUnban
+		if banned {
+			updates["banned_at"] = time.Now()
+			updates["banned_by"] = actor
+			updates["ban_reason"] = reason
+			action = domain.BanActionBan
+		}
+
+		res := tx.Model(&domain.User{}).Where("id = ?", userID).Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("user %s: %w", userID, domain.ErrNotFound)
+		}
+
+		return tx.Create(&domain.BanAudit{
+			UserID: userID,
+			Action: action,
+			Actor:  actor,
+			Reason: reason,
+		}).Error
+	})
+}
+
+func (r *PostgresRepository) CountBlockedEvents(ctx context.Context, userID string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.FraudEvent{}).
+		Where("user_id = ? AND is_blocked = ? AND created_at >= ?", userID, true, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *PostgresRepository) GetBanAudit(ctx context.Context, userID string) ([]domain.BanAudit, error) {
+	var audit []domain.BanAudit
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&audit).Error
+	return audit, err
+}
+
+func (r *PostgresRepository) UpdateUserRisk(ctx context.Context, userID string, score int, changes []domain.RiskScoreChange) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.User{}).Where("id = ?", userID).Updates(map[string]any{
+			"risk_score":      score,
+			"risk_updated_at": time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return tx.Create(&changes).Error
+	})
+}
+
+func (r *PostgresRepository) GetRiskHistory(ctx context.Context, userID string, limit int) ([]domain.RiskScoreChange, error) {
+	var history []domain.RiskScoreChange
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&history).Error
+	return history, err
+}
+
+func (r *PostgresRepository) GetLastRiskChange(ctx context.Context, userID string, causes []domain.RiskCause) (*domain.RiskScoreChange, error) {
+	var change domain.RiskScoreChange
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND cause IN ?", userID, causes).
+		Order("created_at DESC").
+		First(&change).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &change, err
+}
+
+func (r *PostgresRepository) GetMerchant(ctx context.Context, name string) (*domain.Merchant, error) {
+	var m domain.Merchant
+	err := r.db.WithContext(ctx).First(&m, "name = ?", name).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &m, err
+}
+
+func (r *PostgresRepository) ListMerchants(ctx context.Context) ([]domain.Merchant, error) {
+	var merchants []domain.Merchant
+	err := r.db.WithContext(ctx).Order("name").Find(&merchants).Error
+	return merchants, err
+}
+
+func (r *PostgresRepository) UpsertMerchant(ctx context.Context, m *domain.Merchant) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"category", "mcc", "risk_tier", "country", "is_blocked", "updated_by", "updated_at"}),
+	}).Create(m).Error
+}
+
+func (r *PostgresRepository) DeleteMerchant(ctx context.Context, name string) error {
+	res := r.db.WithContext(ctx).Delete(&domain.Merchant{}, "name = ?", name)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("merchant %q: %w", name, domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) RecordLinks(ctx context.Context, userID string, refs []domain.EntityRef, at time.Time) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	links := make([]domain.EntityLink, len(refs))
+	for i, ref := range refs {
+		links[i] = domain.EntityLink{
+			UserID:      userID,
+			EntityType:  ref.Type,
+			EntityValue: ref.Value,
+			TxCount:     1,
+			FirstSeen:   at,
+			LastSeen:    at,
+		}
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "entity_type"}, {Name: "entity_value"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"tx_count":  gorm.Expr("entity_links.tx_count + 1"),
+			"last_seen": gorm.Expr("GREATEST(entity_links.last_seen, EXCLUDED.last_seen)"),
+		}),
+	}).Create(&links).Error
+}
+
+func (r *PostgresRepository) GetLinksByUsers(ctx context.Context, userIDs []string, since time.Time) ([]domain.EntityLink, error) {
+	var links []domain.EntityLink
+	err := r.db.WithContext(ctx).
+		Where("user_id IN ? AND last_seen >= ?", userIDs, since).
+		Order("user_id, entity_type, last_seen DESC").
+		Find(&links).Error
+	return links, err
+}
+
+func (r *PostgresRepository) GetLinksByEntities(ctx context.Context, refs []domain.EntityRef, since time.Time, limit int) ([]domain.EntityLink, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	pairs := make([][]any, len(refs))
+	for i, ref := range refs {
+		pairs[i] = []any{ref.Type, ref.Value}
+	}
+
+	var links []domain.EntityLink
+	err := r.db.WithContext(ctx).
+		Where("(entity_type, entity_value) IN ? AND last_seen >= ?", pairs, since).
+		Order("last_seen DESC").
+		Limit(limit).
+		Find(&links).Error
+	return links, err
+}
+
+func (r *PostgresRepository) GetTrustedEntities(ctx context.Context, userID string) ([]domain.TrustedEntity, error) {
+	var entries []domain.TrustedEntity
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("entity_type, entity_value").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *PostgresRepository) UpsertTrustedEntity(ctx context.Context, e *domain.TrustedEntity) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "entity_type"}, {Name: "entity_value"}},
+		DoUpdates: clause.AssignmentColumns([]string{"source", "expires_at", "created_by", "note", "updated_at"}),
+	}).Create(e).Error
+}
+
+func (r *PostgresRepository) DeleteTrustedEntity(ctx context.Context, userID string, ref domain.EntityRef) error {
+	res := r.db.WithContext(ctx).Delete(&domain.TrustedEntity{},
+		"user_id = ? AND entity_type = ? AND entity_value = ?", userID, ref.Type, ref.Value)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("trusted %s %q: %w", ref.Type, ref.Value, domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) RecordCleanEntities(ctx context.Context, userID string, refs []domain.EntityRef, at, expiresAt time.Time) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	entries := make([]domain.TrustedEntity, len(refs))
+	for i, ref := range refs {
+		entries[i] = domain.TrustedEntity{
+			UserID:      userID,
+			EntityType:  ref.Type,
+			EntityValue: ref.Value,
+			Source:      domain.TrustLearned,
+			CleanCount:  1,
+			LastSeen:    &at,
+			ExpiresAt:   &expiresAt,
+		}
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "entity_type"}, {Name: "entity_value"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"clean_count": gorm.Expr("trusted_entities.clean_count + 1"),
+			"last_seen":   gorm.Expr("GREATEST(trusted_entities.last_seen, EXCLUDED.last_seen)"),
+			"expires_at": gorm.Expr(`CASE WHEN trusted_entities.source = ?
+				THEN GREATEST(trusted_entities.expires_at, EXCLUDED.expires_at)
+				ELSE trusted_entities.expires_at END`, domain.TrustLearned),
+			"updated_at": at,
+		}),
+	}).Create(&entries).Error
+}
+
+func (r *PostgresRepository) ForgetLearnedEntities(ctx context.Context, userID string, refs []domain.EntityRef) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	pairs := make([][]any, len(refs))
+	for i, ref := range refs {
+		pairs[i] = []any{ref.Type, ref.Value}
 	}
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND source = ? AND (entity_type, entity_value) IN ?", userID, domain.TrustLearned, pairs).
+		Delete(&domain.TrustedEntity{}).Error
+}
 
+func (r *PostgresRepository) SaveShadowDecision(ctx context.Context, d *domain.ShadowDecision) error {
+	return r.db.WithContext(ctx).Create(d).Error
+}
+
+func (r *PostgresRepository) ShadowReport(ctx context.Context, since time.Time) ([]domain.ShadowReport, error) {
+	var reports []domain.ShadowReport
 	err := r.db.WithContext(ctx).Raw(`
-		SELECT COALESCE(MAX(amount),0) as max_amount, COALESCE(AVG(amount),0) as avg_amount 
-		FROM fraud_events WHERE user_id = ?`, userID).Scan(&stats).Error
+		SELECT shadow,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE shadow_decision = live_decision) AS agreements,
+			COUNT(*) FILTER (WHERE shadow_decision = 'BLOCK' AND live_decision <> 'BLOCK') AS extra_blocks,
+			COUNT(*) FILTER (WHERE live_decision = 'BLOCK' AND shadow_decision <> 'BLOCK') AS missed_blocks,
+			COUNT(*) FILTER (WHERE shadow_decision = 'REVIEW' AND live_decision <> 'REVIEW') AS extra_reviews,
+			COUNT(*) FILTER (WHERE live_decision = 'REVIEW' AND shadow_decision <> 'REVIEW') AS missed_reviews,
+			COALESCE(AVG(shadow_score - live_score), 0) AS avg_score_delta
+		FROM shadow_decisions
+		WHERE created_at >= ?
+		GROUP BY shadow
+		ORDER BY shadow`, since).
+		Scan(&reports).Error
+	return reports, err
+}
+
+// SaveAMLCase keeps the first case raised for a transaction, so redelivered
+// messages do not duplicate it.
+func (r *PostgresRepository) SaveAMLCase(ctx context.Context, c *domain.AMLCase) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "transaction_id"}},
+		DoNothing: true,
+	}).Create(c).Error
+}
+
+func (r *PostgresRepository) ListAMLCases(ctx context.Context, status domain.AMLCaseStatus, limit int) ([]domain.AMLCase, error) {
+	var cases []domain.AMLCase
+	q := r.db.WithContext(ctx).Order("created_at DESC").Limit(limit)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	err := q.Find(&cases).Error
+	return cases, err
+}
+
+func (r *PostgresRepository) SaveFeatureSnapshot(ctx context.Context, s *domain.FeatureSnapshot) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "transaction_id"}},
+		DoNothing: true,
+	}).Create(s).Error
+}
+
+func (r *PostgresRepository) GetFeatureSnapshot(ctx context.Context, userID string, at time.Time) (*domain.FeatureSnapshot, error) {
+	var s domain.FeatureSnapshot
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND at <= ?", userID, at).
+		Order("at DESC, id DESC").
+		First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &s, err
+}
+
+// CreateCase ignores a transaction that already has a case, so redelivered
+// messages do not queue it twice.
+func (r *PostgresRepository) CreateCase(ctx context.Context, c *domain.ReviewCase) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "transaction_id"}},
+		DoNothing: true,
+	}).Create(c).Error
+}
+
+func (r *PostgresRepository) GetCase(ctx context.Context, id uint) (*domain.ReviewCase, error) {
+	var c domain.ReviewCase
+	err := r.db.WithContext(ctx).First(&c, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &c, err
+}
+
+// ListCases returns the oldest cases first, the order a queue is worked in.
+func (r *PostgresRepository) ListCases(ctx context.Context, status domain.CaseStatus, assignee string, limit int) ([]domain.ReviewCase, error) {
+	q := r.db.WithContext(ctx).Order("created_at, id").Limit(limit)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if assignee != "" {
+		q = q.Where("assigned_to = ?", assignee)
+	}
+	var cases []domain.ReviewCase
+	err := q.Find(&cases).Error
+	return cases, err
+}
+
+func (r *PostgresRepository) UpdateCase(ctx context.Context, c *domain.ReviewCase) error {
+	return r.db.WithContext(ctx).Save(c).Error
+}
+
+func (r *PostgresRepository) LabelFraudEvent(ctx context.Context, l domain.FraudLabel) (*domain.FraudEvent, bool, error) {
+	var event domain.FraudEvent
+	found, changed := false, false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("transaction_id = ?", l.TransactionID).
+			First(&event).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+		if event.Label == l.Label {
+			return nil
+		}
+
+		event.Label = l.Label
+		event.LabelSource = l.Source
+		event.LabeledAt = &l.ReportedAt
+		changed = true
+		return tx.Model(&event).Updates(map[string]any{
+			"label":        l.Label,
+			"label_source": l.Source,
+			"labeled_at":   l.ReportedAt,
+		}).Error
+	})
+	if err != nil || !found {
+		return nil, false, err
+	}
+	return &event, changed, nil
+}
 
-	return stats.MaxAmount, stats.AvgAmount, err
+// LabelMetrics counts the labelled decisions made since the given time by
+// outcome; BLOCK and REVIEW are positives.
+func (r *PostgresRepository) LabelMetrics(ctx context.Context, since time.Time) (*domain.LabelMetrics, error) {
+	var m domain.LabelMetrics
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT COUNT(*) AS decisions,
+			COUNT(*) FILTER (WHERE label <> '') AS labeled,
+			COUNT(*) FILTER (WHERE label = 'fraud') AS fraud,
+			COUNT(*) FILTER (WHERE label = 'legit') AS legit,
+			COUNT(*) FILTER (WHERE label = 'fraud' AND decision <> 'ALLOW') AS true_positives,
+			COUNT(*) FILTER (WHERE label = 'legit' AND decision <> 'ALLOW') AS false_positives,
+			COUNT(*) FILTER (WHERE label = 'fraud' AND decision = 'ALLOW') AS false_negatives,
+			COUNT(*) FILTER (WHERE label = 'legit' AND decision = 'ALLOW') AS true_negatives
+		FROM fraud_events
+		WHERE created_at >= ?`, since).
+		Scan(&m).Error
+	return &m, err
 }
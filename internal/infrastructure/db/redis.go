@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -12,10 +16,11 @@ import (
 )
 
 const (
-	velocityTTL   = time.Minute
-	statsTTL      = 10 * time.Minute
-	aiRiskTTL     = 5 * time.Minute
-	scanBatchSize = 10
+	profileTTL = 30 * 24 * time.Hour
+	geoTTL     = 30 * 24 * time.Hour
+	historyTTL = 365 * 24 * time.Hour
+
+	historyUserField = "user"
 )
 
 type RedisRepository struct {
@@ -26,56 +31,167 @@ func NewRedisRepository(rdb *redis.Client) *RedisRepository {
 	return &RedisRepository{rdb: rdb}
 }
 
-func (r *RedisRepository) GetVelocity(ctx context.Context, userID string) (int, error) {
-	pattern := fmt.Sprintf("velocity:%s:*", userID)
-	var cursor uint64
-	count := 0
+func (r *RedisRepository) GetActivity(ctx context.Context, userID string, since time.Time) ([]domain.Activity, error) {
+	key := fmt.Sprintf("velocity:%s", userID)
+	members, err := r.rdb.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(since.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
 
-	for {
-		keys, nextCursor, err := r.rdb.Scan(ctx, cursor, pattern, scanBatchSize).Result()
-		if err != nil {
-			return 0, err
+	activity := make([]domain.Activity, 0, len(members))
+	for _, m := range members {
+		var a domain.Activity
+		if err := json.Unmarshal([]byte(m), &a); err != nil {
+			return nil, err
+		}
+		activity = append(activity, a)
+	}
+	return activity, nil
+}
+
+// GetActivityStats counts transactions and distinct locations with ZCOUNT and
+// sums amounts from per-minute buckets, so the sums are exact to the minute.
+// Buckets older than the longest window are dropped as they are read.
+func (r *RedisRepository) GetActivityStats(ctx context.Context, userID string, now time.Time, windows []time.Duration) ([]domain.ActivityStats, error) {
+	if len(windows) == 0 {
+		return nil, nil
+	}
+	until := strconv.FormatInt(now.UnixMilli(), 10)
+
+	pipe := r.rdb.Pipeline()
+	counts := make([]*redis.IntCmd, len(windows))
+	locations := make([]*redis.IntCmd, len(windows))
+	for i, w := range windows {
+		since := "(" + strconv.FormatInt(now.Add(-w).UnixMilli(), 10)
+		counts[i] = pipe.ZCount(ctx, fmt.Sprintf("velocity:%s", userID), since, until)
+		locations[i] = pipe.ZCount(ctx, fmt.Sprintf("velocity_locations:%s", userID), since, until)
+	}
+	amounts := pipe.HGetAll(ctx, fmt.Sprintf("velocity_amounts:%s", userID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	oldest := activityBucket(now.Add(-slices.Max(windows)))
+	buckets := make(map[int64]float64)
+	var stale []string
+	for field, val := range amounts.Val() {
+		bucket, err := strconv.ParseInt(field, 10, 64)
+		if err != nil || bucket < oldest {
+			stale = append(stale, field)
+			continue
+		}
+		buckets[bucket], _ = strconv.ParseFloat(val, 64)
+	}
+	if len(stale) > 0 {
+		if err := r.rdb.HDel(ctx, fmt.Sprintf("velocity_amounts:%s", userID), stale...).Err(); err != nil {
+			return nil, err
 		}
-		count += len(keys)
-		cursor = nextCursor
-		if cursor == 0 {
-			break
+	}
+
+	stats := make([]domain.ActivityStats, len(windows))
+	last := activityBucket(now)
+	for i, w := range windows {
+		first := activityBucket(now.Add(-w))
+		for bucket, sum := range buckets {
+			if bucket >= first && bucket <= last {
+				stats[i].AmountSum += sum
+			}
 		}
+		stats[i].Count = int(counts[i].Val())
+		stats[i].Locations = int(locations[i].Val())
 	}
-	return count, nil
+	return stats, nil
 }
 
-func (r *RedisRepository) IncrementVelocity(ctx context.Context, userID, location string) error {
-	key := fmt.Sprintf("velocity:%s:%s", userID, location)
+func (r *RedisRepository) RecordActivity(ctx context.Context, userID string, activity domain.Activity, retention time.Duration) error {
+	key := fmt.Sprintf("velocity:%s", userID)
+	locationsKey := fmt.Sprintf("velocity_locations:%s", userID)
+	amountsKey := fmt.Sprintf("velocity_amounts:%s", userID)
+	data, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+	at := float64(activity.At.UnixMilli())
+	cutoff := strconv.FormatInt(activity.At.Add(-retention).UnixMilli(), 10)
+
 	pipe := r.rdb.Pipeline()
-	pipe.Incr(ctx, key)
-	pipe.Expire(ctx, key, velocityTTL)
-	_, err := pipe.Exec(ctx)
+	pipe.ZAdd(ctx, key, redis.Z{Score: at, Member: data})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+cutoff)
+	pipe.Expire(ctx, key, retention)
+	pipe.ZAddGT(ctx, locationsKey, redis.Z{Score: at, Member: strings.ToLower(activity.Location)})
+	pipe.ZRemRangeByScore(ctx, locationsKey, "-inf", "("+cutoff)
+	pipe.Expire(ctx, locationsKey, retention)
+	pipe.HIncrByFloat(ctx, amountsKey, strconv.FormatInt(activityBucket(activity.At), 10), activity.Amount)
+	pipe.Expire(ctx, amountsKey, retention)
+	_, err = pipe.Exec(ctx)
 	return err
 }
 
-func (r *RedisRepository) GetUserStats(ctx context.Context, userID string) (float64, float64, error) {
-	key := fmt.Sprintf("user_stats:%s", userID)
+// activityBucket is the minute the activity's amount is summed into.
+func activityBucket(at time.Time) int64 {
+	return at.Unix() / 60
+}
+
+func (r *RedisRepository) GetAmountProfile(ctx context.Context, userID string) (*domain.AmountProfile, error) {
+	key := fmt.Sprintf("amount_profile:%s", userID)
 	val, err := r.rdb.Get(ctx, key).Result()
 	if errors.Is(err, redis.Nil) {
-		return 0, 0, nil
+		return nil, nil
 	} else if err != nil {
-		return 0, 0, err
+		return nil, err
+	}
+
+	var profile domain.AmountProfile
+	if err := json.Unmarshal([]byte(val), &profile); err != nil {
+		return nil, err
 	}
+	return &profile, nil
+}
 
-	var maxAmount, avgAmount float64
-	_, err = fmt.Sscanf(val, "%f|%f", &maxAmount, &avgAmount)
-	return maxAmount, avgAmount, err
+func (r *RedisRepository) SetAmountProfile(ctx context.Context, userID string, profile *domain.AmountProfile) error {
+	key := fmt.Sprintf("amount_profile:%s", userID)
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, key, data, profileTTL).Err()
 }
 
-func (r *RedisRepository) SetUserStats(ctx context.Context, userID string, maxAmount, avgAmount float64) error {
-	key := fmt.Sprintf("user_stats:%s", userID)
-	return r.rdb.Set(ctx, key, fmt.Sprintf("%.2f|%.2f", maxAmount, avgAmount), statsTTL).Err()
+// Risk cache entries are keyed by the verdict's context plus per-user and
+// per-merchant generations. Invalidation bumps a generation, which orphans
+// the old entries until their TTL runs out.
+func (r *RedisRepository) riskCacheKey(ctx context.Context, key domain.RiskCacheKey) (string, error) {
+	merchant := entityHash(key.Merchant)
+	gens, err := r.rdb.MGet(ctx, "ai_risk:gen:user:"+key.UserID, "ai_risk:gen:merchant:"+merchant).Result()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ai_risk:%s:%v:%s:%v:%d:%s",
+		key.UserID, genOrZero(gens[0]), merchant, genOrZero(gens[1]), key.AmountBand, entityHash(key.Location)), nil
 }
 
-func (r *RedisRepository) GetRiskCache(ctx context.Context, userID, merchant string) (*domain.FraudAlert, error) {
-	key := fmt.Sprintf("ai_risk:%s:%s", userID, merchant)
-	val, err := r.rdb.Get(ctx, key).Result()
+func genOrZero(v any) any {
+	if v == nil {
+		return 0
+	}
+	return v
+}
+
+func entityHash(value string) string {
+	h := fnv.New64a()
+	h.Write([]byte(value))
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+func (r *RedisRepository) GetRiskCache(ctx context.Context, key domain.RiskCacheKey) (*domain.FraudAlert, error) {
+	k, err := r.riskCacheKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	val, err := r.rdb.Get(ctx, k).Result()
 	if errors.Is(err, redis.Nil) {
 		return nil, nil
 	} else if err != nil {
@@ -89,8 +205,207 @@ func (r *RedisRepository) GetRiskCache(ctx context.Context, userID, merchant str
 	return &alert, nil
 }
 
-func (r *RedisRepository) SetRiskCache(ctx context.Context, userID, merchant string, alert domain.FraudAlert) error {
-	key := fmt.Sprintf("ai_risk:%s:%s", userID, merchant)
-	data, _ := json.Marshal(alert)
-	return r.rdb.Set(ctx, key, data, aiRiskTTL).Err()
+func (r *RedisRepository) SetRiskCache(ctx context.Context, key domain.RiskCacheKey, alert domain.FraudAlert, ttl time.Duration) error {
+	k, err := r.riskCacheKey(ctx, key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, k, data, ttl).Err()
+}
+
+func (r *RedisRepository) InvalidateUserRisk(ctx context.Context, userID string) error {
+	return r.rdb.Incr(ctx, "ai_risk:gen:user:"+userID).Err()
+}
+
+func (r *RedisRepository) InvalidateMerchantRisk(ctx context.Context, merchant string) error {
+	return r.rdb.Incr(ctx, "ai_risk:gen:merchant:"+entityHash(merchant)).Err()
+}
+
+func (r *RedisRepository) GetLastPosition(ctx context.Context, userID string) (*domain.GeoPosition, error) {
+	key := fmt.Sprintf("geo:last:%s", userID)
+	val, err := r.rdb.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var pos domain.GeoPosition
+	if err := json.Unmarshal([]byte(val), &pos); err != nil {
+		return nil, err
+	}
+	return &pos, nil
+}
+
+func (r *RedisRepository) SetLastPosition(ctx context.Context, userID string, pos domain.GeoPosition) error {
+	key := fmt.Sprintf("geo:last:%s", userID)
+	data, _ := json.Marshal(pos)
+	return r.rdb.Set(ctx, key, data, geoTTL).Err()
+}
+
+func (r *RedisRepository) RecordIPUse(ctx context.Context, ip, userID, txID string, at time.Time, window time.Duration) error {
+	usersKey := fmt.Sprintf("ip:users:%s", ip)
+	txKey := fmt.Sprintf("ip:tx:%s", ip)
+	score := float64(at.UnixMilli())
+	cutoff := strconv.FormatInt(at.Add(-window).UnixMilli(), 10)
+
+	pipe := r.rdb.Pipeline()
+	pipe.ZAdd(ctx, usersKey, redis.Z{Score: score, Member: userID})
+	pipe.ZAdd(ctx, txKey, redis.Z{Score: score, Member: txID})
+	pipe.ZRemRangeByScore(ctx, usersKey, "-inf", "("+cutoff)
+	pipe.ZRemRangeByScore(ctx, txKey, "-inf", "("+cutoff)
+	pipe.Expire(ctx, usersKey, window)
+	pipe.Expire(ctx, txKey, window)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisRepository) GetIPUsers(ctx context.Context, ip string, since time.Time) ([]string, error) {
+	key := fmt.Sprintf("ip:users:%s", ip)
+	return r.rdb.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(since.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+}
+
+func (r *RedisRepository) CountIPTransactions(ctx context.Context, ip string, since time.Time) (int64, error) {
+	key := fmt.Sprintf("ip:tx:%s", ip)
+	return r.rdb.ZCount(ctx, key, strconv.FormatInt(since.UnixMilli(), 10), "+inf").Result()
+}
+
+// The entity history is one hash per user. Entity values are stored as
+// 64-bit FNV hashes rather than strings, with the first-seen unix time as
+// the value, so long merchant names and locations cost a fixed few bytes.
+func historyField(ref domain.EntityRef) string {
+	return string(ref.Type) + ":" + entityHash(ref.Value)
+}
+
+func (r *RedisRepository) FirstSeen(ctx context.Context, userID string, refs []domain.EntityRef) (time.Time, map[domain.EntityRef]time.Time, error) {
+	key := fmt.Sprintf("history:%s", userID)
+	fields := make([]string, 0, len(refs)+1)
+	fields = append(fields, historyUserField)
+	for _, ref := range refs {
+		fields = append(fields, historyField(ref))
+	}
+
+	vals, err := r.rdb.HMGet(ctx, key, fields...).Result()
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	parse := func(v any) (time.Time, bool) {
+		str, ok := v.(string)
+		if !ok {
+			return time.Time{}, false
+		}
+		sec, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(sec, 0), true
+	}
+
+	userSeen, _ := parse(vals[0])
+	seen := make(map[domain.EntityRef]time.Time, len(refs))
+	for i, ref := range refs {
+		if at, ok := parse(vals[i+1]); ok {
+			seen[ref] = at
+		}
+	}
+	return userSeen, seen, nil
+}
+
+func (r *RedisRepository) MarkSeen(ctx context.Context, userID string, refs []domain.EntityRef, at time.Time) error {
+	key := fmt.Sprintf("history:%s", userID)
+	ts := strconv.FormatInt(at.Unix(), 10)
+
+	pipe := r.rdb.Pipeline()
+	pipe.HSetNX(ctx, key, historyUserField, ts)
+	for _, ref := range refs {
+		pipe.HSetNX(ctx, key, historyField(ref), ts)
+	}
+	pipe.Expire(ctx, key, historyTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisRepository) RecordNearThreshold(ctx context.Context, userID string, activity domain.Activity, period time.Duration) error {
+	key := fmt.Sprintf("aml:near:%s", userID)
+	data, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+	cutoff := strconv.FormatInt(activity.At.Add(-period).UnixMilli(), 10)
+
+	pipe := r.rdb.Pipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(activity.At.UnixMilli()), Member: data})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+cutoff)
+	pipe.Expire(ctx, key, period)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisRepository) GetNearThreshold(ctx context.Context, userIDs []string, since time.Time) (map[string][]domain.Activity, error) {
+	pipe := r.rdb.Pipeline()
+	cmds := make([]*redis.StringSliceCmd, len(userIDs))
+	for i, userID := range userIDs {
+		cmds[i] = pipe.ZRangeByScore(ctx, fmt.Sprintf("aml:near:%s", userID), &redis.ZRangeBy{
+			Min: strconv.FormatInt(since.UnixMilli(), 10),
+			Max: "+inf",
+		})
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	near := make(map[string][]domain.Activity, len(userIDs))
+	for i, userID := range userIDs {
+		for _, m := range cmds[i].Val() {
+			var a domain.Activity
+			if err := json.Unmarshal([]byte(m), &a); err != nil {
+				return nil, err
+			}
+			near[userID] = append(near[userID], a)
+		}
+	}
+	return near, nil
+}
+
+func (r *RedisRepository) PutFeatureVector(ctx context.Context, userID string, v domain.FeatureVector, retention time.Duration) error {
+	key := fmt.Sprintf("features:%s", userID)
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	cutoff := strconv.FormatInt(v.At.Add(-retention).UnixMilli(), 10)
+
+	pipe := r.rdb.Pipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(v.At.UnixMilli()), Member: data})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+cutoff)
+	pipe.Expire(ctx, key, retention)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// GetFeatureVector returns the latest vector stored at or before at.
+func (r *RedisRepository) GetFeatureVector(ctx context.Context, userID string, at time.Time) (*domain.FeatureVector, error) {
+	key := fmt.Sprintf("features:%s", userID)
+	members, err := r.rdb.ZRevRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(at.UnixMilli(), 10),
+		Count: 1,
+	}).Result()
+	if err != nil || len(members) == 0 {
+		return nil, err
+	}
+
+	var v domain.FeatureVector
+	if err := json.Unmarshal([]byte(members[0]), &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
 }
@@ -10,32 +10,85 @@ import (
 func SeedUsers(db *gorm.DB) error {
 	users := []domain.User{
 		{
-			ID:        "user-1",
-			Email:     "premium.client@example.com",
-			RiskScore: 0,
-			IsBanned:  false,
+			ID:           "user-1",
+			Email:        "premium.client@example.com",
+			RiskScore:    0,
+			RiskBaseline: 0,
+			IsBanned:     false,
 		},
 		{
-			ID:        "user-2",
-			Email:     "flagged.account@example.com",
-			RiskScore: 85,
-			IsBanned:  false,
+			ID:           "user-2",
+			Email:        "flagged.account@example.com",
+			RiskScore:    85,
+			RiskBaseline: 85,
+			IsBanned:     false,
 		},
 		{
-			ID:        "user-3",
-			Email:     "standard.user@example.com",
-			RiskScore: 15,
-			IsBanned:  false,
+			ID:           "user-3",
+			Email:        "standard.user@example.com",
+			RiskScore:    15,
+			RiskBaseline: 15,
+			IsBanned:     false,
 		},
 	}
 
 	for _, u := range users {
-		if err := db.FirstOrCreate(&u, domain.User{ID: u.ID}).Error; err != nil {
-			slog.Error("Failed to seed user", "user_id", u.ID, "err", err)
+		risk := map[string]any{"risk_score": u.RiskScore, "risk_baseline": u.RiskBaseline}
+		res := db.FirstOrCreate(&u, domain.User{ID: u.ID})
+		if res.Error == nil && res.RowsAffected > 0 {
+			// The insert skips zero scores in favour of the column defaults.
+			res = db.Model(&domain.User{}).Where("id = ?", u.ID).Updates(risk)
+		}
+		if res.Error != nil {
+			slog.Error("Failed to seed user", "user_id", u.ID, "err", res.Error)
+			return res.Error
+		}
+	}
+
+	merchants := []domain.Merchant{
+		{
+			Name:     "Premium Apple Reseller",
+			Category: "Electronics",
+			MCC:      "5732",
+			RiskTier: domain.MerchantRiskLow,
+			Country:  "Ukraine",
+		},
+		{
+			Name:     "Binance P2P Exchange",
+			Category: "Crypto Exchange",
+			MCC:      "6051",
+			RiskTier: domain.MerchantRiskHigh,
+			Country:  "Singapore",
+		},
+		{
+			Name:     "Local Supermarket",
+			Category: "Grocery",
+			MCC:      "5411",
+			RiskTier: domain.MerchantRiskLow,
+			Country:  "Ukraine",
+		},
+	}
+
+	for _, m := range merchants {
+		if err := db.FirstOrCreate(&m, domain.Merchant{Name: m.Name}).Error; err != nil {
+			slog.Error("Failed to seed merchant", "merchant", m.Name, "err", err)
 			return err
 		}
 	}
 
+	// The premium persona buys from the same reseller every time.
+	trusted := domain.TrustedEntity{
+		UserID:      "user-1",
+		EntityType:  domain.EntityMerchant,
+		EntityValue: "Premium Apple Reseller",
+		Source:      domain.TrustManual,
+		CreatedBy:   "seed",
+	}
+	if err := db.FirstOrCreate(&trusted, domain.TrustedEntity{UserID: trusted.UserID, EntityType: trusted.EntityType, EntityValue: trusted.EntityValue}).Error; err != nil {
+		slog.Error("Failed to seed trusted entity", "user_id", trusted.UserID, "err", err)
+		return err
+	}
+
 	slog.Info("Database seeding completed successfully")
 	return nil
 }
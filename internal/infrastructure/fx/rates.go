@@ -0,0 +1,150 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RateFile lists how many units of Base one unit of each currency is worth.
+type RateFile struct {
+	Base  string             `json:"base" yaml:"base"`
+	Rates map[string]float64 `json:"rates" yaml:"rates"`
+}
+
+// Rates serves conversion rates into a fixed base currency and can reload
+// them from the source file while in use.
+type Rates struct {
+	path string
+	base string
+
+	mu        sync.RWMutex
+	file      RateFile
+	updatedAt time.Time
+}
+
+func NewRates(path, base string) (*Rates, error) {
+	r := &Rates{path: path, base: strings.ToUpper(base)}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Rates) Base() string {
+	return r.base
+}
+
+// Rate returns the number of base units one unit of currency is worth.
+// Rates are cross-converted when the file is quoted in another base.
+func (r *Rates) Rate(currency string) (float64, bool) {
+	currency = strings.ToUpper(currency)
+	if currency == r.base {
+		return 1, true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rate, ok := r.quote(currency)
+	if !ok {
+		return 0, false
+	}
+	base, ok := r.quote(r.base)
+	if !ok {
+		return 0, false
+	}
+	return rate / base, true
+}
+
+func (r *Rates) quote(currency string) (float64, bool) {
+	if currency == r.file.Base {
+		return 1, true
+	}
+	rate, ok := r.file.Rates[currency]
+	return rate, ok && rate > 0
+}
+
+func (r *Rates) UpdatedAt() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.updatedAt
+}
+
+func (r *Rates) Reload() error {
+	file, err := loadRateFile(r.path)
+	if err != nil {
+		return err
+	}
+	if r.base != file.Base {
+		if _, ok := file.Rates[r.base]; !ok {
+			return fmt.Errorf("fx rates %s: no rate for base currency %s", r.path, r.base)
+		}
+	}
+
+	r.mu.Lock()
+	r.file = file
+	r.updatedAt = time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+// Run reloads the rates every interval until ctx is done. A failed reload
+// keeps the previous rates.
+func (r *Rates) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Reload(); err != nil {
+				slog.Error("FX rates refresh failed, keeping previous rates", "path", r.path, "err", err)
+				continue
+			}
+			slog.Debug("FX rates refreshed", "path", r.path)
+		}
+	}
+}
+
+func loadRateFile(path string) (RateFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return RateFile{}, fmt.Errorf("read fx rates: %w", err)
+	}
+
+	var file RateFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	default:
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return RateFile{}, fmt.Errorf("parse fx rates %s: %w", path, err)
+	}
+	if file.Base == "" {
+		return RateFile{}, fmt.Errorf("fx rates %s: base currency is required", path)
+	}
+
+	file.Base = strings.ToUpper(file.Base)
+	rates := make(map[string]float64, len(file.Rates))
+	for currency, rate := range file.Rates {
+		if rate <= 0 {
+			return RateFile{}, fmt.Errorf("fx rates %s: rate for %s must be positive", path, currency)
+		}
+		rates[strings.ToUpper(currency)] = rate
+	}
+	file.Rates = rates
+	return file, nil
+}
@@ -0,0 +1,104 @@
+package geo
+
+import "github.com/tokyosplif/fraud-core/internal/domain"
+
+type city struct {
+	name    string
+	country string
+	lat     float64
+	lon     float64
+}
+
+var cities = []city{
+	// Ukraine
+	{"Kyiv", "Ukraine", 50.4501, 30.5234},
+	{"Kiev", "Ukraine", 50.4501, 30.5234},
+	{"Lviv", "Ukraine", 49.8397, 24.0297},
+	{"Kharkiv", "Ukraine", 49.9935, 36.2304},
+	{"Odesa", "Ukraine", 46.4825, 30.7233},
+	{"Odessa", "Ukraine", 46.4825, 30.7233},
+	{"Dnipro", "Ukraine", 48.4647, 35.0462},
+	{"Zaporizhzhia", "Ukraine", 47.8388, 35.1396},
+	{"Vinnytsia", "Ukraine", 49.2331, 28.4682},
+	{"Ivano-Frankivsk", "Ukraine", 48.9226, 24.7111},
+	{"Uzhhorod", "Ukraine", 48.6208, 22.2879},
+	{"Chernivtsi", "Ukraine", 48.2915, 25.9403},
+
+	// Europe
+	{"Warsaw", "Poland", 52.2297, 21.0122},
+	{"Krakow", "Poland", 50.0647, 19.9450},
+	{"Berlin", "Germany", 52.5200, 13.4050},
+	{"Munich", "Germany", 48.1351, 11.5820},
+	{"Frankfurt", "Germany", 50.1109, 8.6821},
+	{"London", "United Kingdom", 51.5074, -0.1278},
+	{"Paris", "France", 48.8566, 2.3522},
+	{"Madrid", "Spain", 40.4168, -3.7038},
+	{"Barcelona", "Spain", 41.3851, 2.1734},
+	{"Rome", "Italy", 41.9028, 12.4964},
+	{"Milan", "Italy", 45.4642, 9.1900},
+	{"Amsterdam", "Netherlands", 52.3676, 4.9041},
+	{"Brussels", "Belgium", 50.8503, 4.3517},
+	{"Vienna", "Austria", 48.2082, 16.3738},
+	{"Prague", "Czech Republic", 50.0755, 14.4378},
+	{"Budapest", "Hungary", 47.4979, 19.0402},
+	{"Bucharest", "Romania", 44.4268, 26.1025},
+	{"Chisinau", "Moldova", 47.0105, 28.8638},
+	{"Vilnius", "Lithuania", 54.6872, 25.2797},
+	{"Riga", "Latvia", 56.9496, 24.1052},
+	{"Tallinn", "Estonia", 59.4370, 24.7536},
+	{"Stockholm", "Sweden", 59.3293, 18.0686},
+	{"Oslo", "Norway", 59.9139, 10.7522},
+	{"Copenhagen", "Denmark", 55.6761, 12.5683},
+	{"Helsinki", "Finland", 60.1699, 24.9384},
+	{"Dublin", "Ireland", 53.3498, -6.2603},
+	{"Lisbon", "Portugal", 38.7223, -9.1393},
+	{"Zurich", "Switzerland", 47.3769, 8.5417},
+	{"Athens", "Greece", 37.9838, 23.7275},
+	{"Istanbul", "Turkey", 41.0082, 28.9784},
+
+	// Americas
+	{"New York", "United States", 40.7128, -74.0060},
+	{"Los Angeles", "United States", 34.0522, -118.2437},
+	{"Chicago", "United States", 41.8781, -87.6298},
+	{"Miami", "United States", 25.7617, -80.1918},
+	{"San Francisco", "United States", 37.7749, -122.4194},
+	{"Toronto", "Canada", 43.6532, -79.3832},
+	{"Mexico City", "Mexico", 19.4326, -99.1332},
+	{"Sao Paulo", "Brazil", -23.5505, -46.6333},
+	{"Buenos Aires", "Argentina", -34.6037, -58.3816},
+	{"Bogota", "Colombia", 4.7110, -74.0721},
+
+	// Asia & Middle East
+	{"Singapore", "Singapore", 1.3521, 103.8198},
+	{"Hong Kong", "Hong Kong", 22.3193, 114.1694},
+	{"Tokyo", "Japan", 35.6762, 139.6503},
+	{"Seoul", "South Korea", 37.5665, 126.9780},
+	{"Beijing", "China", 39.9042, 116.4074},
+	{"Shanghai", "China", 31.2304, 121.4737},
+	{"Bangkok", "Thailand", 13.7563, 100.5018},
+	{"Jakarta", "Indonesia", -6.2088, 106.8456},
+	{"Manila", "Philippines", 14.5995, 120.9842},
+	{"Mumbai", "India", 19.0760, 72.8777},
+	{"Delhi", "India", 28.7041, 77.1025},
+	{"Dubai", "United Arab Emirates", 25.2048, 55.2708},
+	{"Tel Aviv", "Israel", 32.0853, 34.7818},
+	{"Tbilisi", "Georgia", 41.7151, 44.8271},
+	{"Almaty", "Kazakhstan", 43.2220, 76.8512},
+
+	// Africa & Oceania
+	{"Lagos", "Nigeria", 6.5244, 3.3792},
+	{"Abuja", "Nigeria", 9.0765, 7.3986},
+	{"Accra", "Ghana", 5.6037, -0.1870},
+	{"Nairobi", "Kenya", -1.2921, 36.8219},
+	{"Cairo", "Egypt", 30.0444, 31.2357},
+	{"Johannesburg", "South Africa", -26.2041, 28.0473},
+	{"Cape Town", "South Africa", -33.9249, 18.4241},
+	{"Casablanca", "Morocco", 33.5731, -7.5898},
+	{"Sydney", "Australia", -33.8688, 151.2093},
+	{"Melbourne", "Australia", -37.8136, 144.9631},
+	{"Auckland", "New Zealand", -36.8485, 174.7633},
+}
+
+func (c city) point() domain.GeoPoint {
+	return domain.GeoPoint{Lat: c.lat, Lon: c.lon, Country: c.country}
+}
@@ -0,0 +1,38 @@
+package geo
+
+import (
+	"strings"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type CityResolver struct {
+	byName map[string]domain.GeoPoint
+}
+
+func NewCityResolver() *CityResolver {
+	r := &CityResolver{byName: make(map[string]domain.GeoPoint, len(cities)*2)}
+	for _, c := range cities {
+		r.byName[normalize(c.name)] = c.point()
+		r.byName[normalize(c.name+", "+c.country)] = c.point()
+	}
+	return r
+}
+
+// Resolve accepts "City" or "City, Country" strings, as produced by the
+// simulator and upstream payment gateways.
+func (r *CityResolver) Resolve(location string) (domain.GeoPoint, bool) {
+	key := normalize(location)
+	if p, ok := r.byName[key]; ok {
+		return p, true
+	}
+	if name, _, found := strings.Cut(key, ","); found {
+		p, ok := r.byName[strings.TrimSpace(name)]
+		return p, ok
+	}
+	return domain.GeoPoint{}, false
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
@@ -0,0 +1,144 @@
+package grpc_client
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCircuitOpen  = errors.New("risk engine circuit open")
+	ErrBulkheadFull = errors.New("risk engine concurrency limit reached")
+)
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// breaker opens after failureThreshold consecutive failures and rejects
+// calls for openTimeout. It then lets up to halfOpenProbes calls through:
+// a success closes it again, a failure reopens it. Every state change
+// starts a new generation; outcomes of calls admitted in an earlier one are
+// ignored, so a slow call from before the breaker opened never counts as a
+// probe.
+type breaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenProbes   int
+	onChange         func(from, to BreakerState, failures int)
+	now              func() time.Time
+
+	mu         sync.Mutex
+	state      BreakerState
+	generation uint64
+	failures   int
+	openedAt   time.Time
+	probes     int
+}
+
+// admission identifies an allowed call to done and release.
+type admission struct {
+	generation uint64
+	probe      bool
+}
+
+func newBreaker(failureThreshold int, openTimeout time.Duration, halfOpenProbes int, onChange func(from, to BreakerState, failures int)) *breaker {
+	return &breaker{
+		failureThreshold: max(failureThreshold, 1),
+		openTimeout:      openTimeout,
+		halfOpenProbes:   max(halfOpenProbes, 1),
+		onChange:         onChange,
+		now:              time.Now,
+	}
+}
+
+func (b *breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// allow reports whether a call may go through. Every allowed call must be
+// followed by done or release with the returned admission.
+func (b *breaker) allow() (admission, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen {
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return admission{}, ErrCircuitOpen
+		}
+		b.transition(BreakerHalfOpen)
+	}
+	if b.state == BreakerHalfOpen {
+		if b.probes >= b.halfOpenProbes {
+			return admission{}, ErrCircuitOpen
+		}
+		b.probes++
+		return admission{generation: b.generation, probe: true}, nil
+	}
+	return admission{generation: b.generation}, nil
+}
+
+// release returns an allowed call that ended without telling anything about
+// the engine's health.
+func (b *breaker) release(a admission) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if a.probe && a.generation == b.generation {
+		b.probes--
+	}
+}
+
+// done records the outcome of an allowed call.
+func (b *breaker) done(a admission, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if a.generation != b.generation {
+		return
+	}
+	if a.probe {
+		b.probes--
+	}
+	if success {
+		b.failures = 0
+		if b.state != BreakerClosed {
+			b.transition(BreakerClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || (b.state == BreakerClosed && b.failures >= b.failureThreshold) {
+		b.openedAt = b.now()
+		b.transition(BreakerOpen)
+	}
+}
+
+func (b *breaker) transition(to BreakerState) {
+	from := b.state
+	b.state = to
+	b.generation++
+	if to != BreakerHalfOpen {
+		b.probes = 0
+	}
+	if b.onChange != nil {
+		b.onChange(from, to, b.failures)
+	}
+}
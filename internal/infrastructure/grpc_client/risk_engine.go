@@ -2,7 +2,11 @@ package grpc_client
 
 import (
 	"context"
+	"errors"
+	"expvar"
 	"fmt"
+	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/avast/retry-go"
@@ -12,36 +16,147 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-const (
-	requestTimeout = 10 * time.Second
-	retryAttempts  = 3
-	retryDelay     = 200 * time.Millisecond
+const retryDelay = 100 * time.Millisecond
+
+// clients publishes the metrics of every RiskClient under "risk_engine" in
+// expvar's /debug/vars, keyed by engine address.
+var (
+	clientsMu sync.Mutex
+	clients   = expvar.NewMap("risk_engine")
 )
 
+// publishMetrics registers a client's metrics and returns their key, which
+// is suffixed when another client already uses the address.
+func publishMetrics(addr string, m *expvar.Map) string {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	key := addr
+	for n := 2; clients.Get(key) != nil; n++ {
+		key = fmt.Sprintf("%s#%d", addr, n)
+	}
+	clients.Set(key, m)
+	return key
+}
+
+// ClientConfig bounds how much a slow or failing risk engine can cost each
+// transaction.
+type ClientConfig struct {
+	// Budget is the total time one Analyze call may take, retries included.
+	Budget time.Duration
+	// Attempts is the maximum number of tries within the budget.
+	Attempts int
+	// MaxConcurrent caps in-flight calls; calls over the cap fail at once.
+	MaxConcurrent int
+	// FailureThreshold consecutive failures open the circuit for OpenTimeout,
+	// after which HalfOpenProbes trial calls decide whether it closes again.
+	FailureThreshold int
+	OpenTimeout      time.Duration
+	HalfOpenProbes   int
+}
+
 type RiskClient struct {
-	client pb.RiskEngineServiceClient
-	conn   *grpc.ClientConn
+	client  pb.RiskEngineServiceClient
+	conn    *grpc.ClientConn
+	cfg     ClientConfig
+	breaker *breaker
+	slots   chan struct{}
+	metrics *expvar.Map
+	key     string
 }
 
-func NewRiskClient(addr string) (*RiskClient, error) {
+func NewRiskClient(addr string, cfg ClientConfig) (*RiskClient, error) {
+	if cfg.Budget <= 0 || cfg.Attempts <= 0 || cfg.MaxConcurrent <= 0 {
+		return nil, fmt.Errorf("risk engine budget, attempts and concurrency must be positive")
+	}
 	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		return nil, err
 	}
-	return &RiskClient{
-		client: pb.NewRiskEngineServiceClient(conn),
-		conn:   conn,
-	}, nil
+
+	c := &RiskClient{
+		client:  pb.NewRiskEngineServiceClient(conn),
+		conn:    conn,
+		cfg:     cfg,
+		slots:   make(chan struct{}, cfg.MaxConcurrent),
+		metrics: new(expvar.Map),
+	}
+	c.breaker = newBreaker(cfg.FailureThreshold, cfg.OpenTimeout, cfg.HalfOpenProbes, c.logTransition)
+	c.metrics.Set("state", expvar.Func(func() any { return c.breaker.State().String() }))
+	c.metrics.Set("in_flight", expvar.Func(func() any { return len(c.slots) }))
+	c.key = publishMetrics(addr, c.metrics)
+	return c, nil
+}
+
+func (c *RiskClient) logTransition(from, to BreakerState, failures int) {
+	c.metrics.Add("transitions", 1)
+	switch to {
+	case BreakerOpen:
+		slog.Warn("Risk engine circuit opened, skipping AI", "from", from.String(), "consecutive_failures", failures)
+	case BreakerHalfOpen:
+		slog.Info("Risk engine circuit half-open, probing")
+	default:
+		slog.Info("Risk engine circuit closed", "from", from.String())
+	}
 }
 
+// State is the current circuit breaker state.
+func (c *RiskClient) State() BreakerState {
+	return c.breaker.State()
+}
+
+// Analyze calls the risk engine within the configured budget. It fails fast
+// with ErrCircuitOpen while the engine is considered unhealthy and with
+// ErrBulkheadFull when too many calls are already in flight.
 func (c *RiskClient) Analyze(ctx context.Context, tx domain.Transaction, user domain.User) (domain.FraudAlert, error) {
-	gCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	c.metrics.Add("calls", 1)
+	select {
+	case c.slots <- struct{}{}:
+	default:
+		c.metrics.Add("rejected_busy", 1)
+		return domain.FraudAlert{}, ErrBulkheadFull
+	}
+	defer func() { <-c.slots }()
+
+	admitted, err := c.breaker.allow()
+	if err != nil {
+		c.metrics.Add("rejected_open", 1)
+		return domain.FraudAlert{}, err
+	}
+
+	start := time.Now()
+	alert, err := c.analyze(ctx, tx, user)
+	c.metrics.Add("latency_ms_total", time.Since(start).Milliseconds())
+
+	switch {
+	case err == nil:
+		c.metrics.Add("successes", 1)
+		c.breaker.done(admitted, true)
+	case ctx.Err() != nil:
+		// The caller gave up, e.g. on shutdown; that says nothing about the
+		// engine's health.
+		c.metrics.Add("canceled", 1)
+		c.breaker.release(admitted)
+	default:
+		c.metrics.Add("failures", 1)
+		if errors.Is(err, context.DeadlineExceeded) {
+			c.metrics.Add("timeouts", 1)
+		}
+		c.breaker.done(admitted, false)
+	}
+	return alert, err
+}
+
+func (c *RiskClient) analyze(ctx context.Context, tx domain.Transaction, user domain.User) (domain.FraudAlert, error) {
+	gCtx, cancel := context.WithTimeout(ctx, c.cfg.Budget)
 	defer cancel()
 
 	userContext := fmt.Sprintf(
 		"risk_score:%d,max_tx:%.2f,avg_tx:%.2f",
 		user.RiskScore, user.MaxTx, user.AvgTx,
 	)
+	if user.Novelty != nil {
+		userContext += "," + user.Novelty.String()
+	}
 
 	req := &pb.AnalyzeRequest{
 		TransactionId:      tx.ID,
@@ -59,8 +174,10 @@ func (c *RiskClient) Analyze(ctx context.Context, tx domain.Transaction, user do
 			resp, err = c.client.AnalyzeTransaction(gCtx, req)
 			return err
 		},
-		retry.Attempts(retryAttempts),
+		retry.Attempts(uint(c.cfg.Attempts)),
 		retry.Delay(retryDelay),
+		retry.Context(gCtx),
+		retry.LastErrorOnly(true),
 	)
 
 	if err != nil {
@@ -79,6 +196,9 @@ func (c *RiskClient) Analyze(ctx context.Context, tx domain.Transaction, user do
 }
 
 func (c *RiskClient) Close() error {
+	clientsMu.Lock()
+	clients.Delete(c.key)
+	clientsMu.Unlock()
 	if c.conn != nil {
 		return c.conn.Close()
 	}
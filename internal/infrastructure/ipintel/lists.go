@@ -0,0 +1,106 @@
+package ipintel
+
+import (
+	"bufio"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type RangeList []netip.Prefix
+
+func (l RangeList) Contains(addr netip.Addr) bool {
+	for _, p := range l {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadRanges reads one CIDR or bare IP per line. Blank lines and anything
+// after '#' are ignored. An empty path yields an empty list.
+func LoadRanges(path string) (RangeList, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open ip list: %w", err)
+	}
+	defer f.Close()
+
+	var list RangeList
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if !strings.Contains(line, "/") {
+			addr, err := netip.ParseAddr(line)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+			}
+			list = append(list, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		list = append(list, prefix.Masked())
+	}
+	return list, scanner.Err()
+}
+
+type Reputation struct {
+	allow RangeList
+	deny  RangeList
+	proxy RangeList
+	tor   RangeList
+}
+
+type Paths struct {
+	Allow string
+	Deny  string
+	Proxy string
+	Tor   string
+}
+
+func NewReputation(paths Paths) (*Reputation, error) {
+	var r Reputation
+	var err error
+	if r.allow, err = LoadRanges(paths.Allow); err != nil {
+		return nil, err
+	}
+	if r.deny, err = LoadRanges(paths.Deny); err != nil {
+		return nil, err
+	}
+	if r.proxy, err = LoadRanges(paths.Proxy); err != nil {
+		return nil, err
+	}
+	if r.tor, err = LoadRanges(paths.Tor); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (r *Reputation) Lookup(addr netip.Addr) domain.IPReputation {
+	return domain.IPReputation{
+		Allowed: r.allow.Contains(addr),
+		Denied:  r.deny.Contains(addr),
+		Proxy:   r.proxy.Contains(addr),
+		Tor:     r.tor.Contains(addr),
+	}
+}
+
+func (r *Reputation) Sizes() (allow, deny, proxy, tor int) {
+	return len(r.allow), len(r.deny), len(r.proxy), len(r.tor)
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+func (s *Store) RecordNearThreshold(ctx context.Context, userID string, activity domain.Activity, period time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cutoff := activity.At.Add(-period)
+	kept := s.near[userID][:0]
+	for _, a := range s.near[userID] {
+		if !a.At.Before(cutoff) {
+			kept = append(kept, a)
+		}
+	}
+	s.near[userID] = append(kept, activity)
+	return nil
+}
+
+func (s *Store) GetNearThreshold(ctx context.Context, userIDs []string, since time.Time) (map[string][]domain.Activity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	near := make(map[string][]domain.Activity, len(userIDs))
+	for _, userID := range userIDs {
+		for _, a := range s.near[userID] {
+			if !a.At.Before(since) {
+				near[userID] = append(near[userID], a)
+			}
+		}
+	}
+	return near, nil
+}
+
+func (s *Store) SaveAMLCase(ctx context.Context, c *domain.AMLCase) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.ContainsFunc(s.amlCases, func(e domain.AMLCase) bool { return e.TransactionID == c.TransactionID }) {
+		return nil
+	}
+	c.ID = uint(len(s.amlCases) + 1)
+	s.amlCases = append(s.amlCases, *c)
+	return nil
+}
+
+// ListAMLCases returns the newest cases first, like the Postgres listing.
+func (s *Store) ListAMLCases(ctx context.Context, status domain.AMLCaseStatus, limit int) ([]domain.AMLCase, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var cases []domain.AMLCase
+	for i := len(s.amlCases) - 1; i >= 0 && len(cases) < limit; i-- {
+		if c := s.amlCases[i]; status == "" || c.Status == status {
+			cases = append(cases, c)
+		}
+	}
+	return cases, nil
+}
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+// Windows are evaluated against the caller's timestamps, not the wall clock,
+// so replayed history ages exactly as it did in production.
+
+func (s *Store) GetActivity(ctx context.Context, userID string, since time.Time) ([]domain.Activity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []domain.Activity
+	for _, a := range s.activity[userID] {
+		if a.At.After(since) {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (s *Store) GetActivityStats(ctx context.Context, userID string, now time.Time, windows []time.Duration) ([]domain.ActivityStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := make([]domain.ActivityStats, len(windows))
+	for i, w := range windows {
+		stats[i] = domain.SummarizeActivity(s.activity[userID], now.Add(-w), now)
+	}
+	return stats, nil
+}
+
+func (s *Store) RecordActivity(ctx context.Context, userID string, activity domain.Activity, retention time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cutoff := activity.At.Add(-retention)
+	kept := s.activity[userID][:0]
+	for _, a := range s.activity[userID] {
+		if !a.At.Before(cutoff) {
+			kept = append(kept, a)
+		}
+	}
+	s.activity[userID] = append(kept, activity)
+	return nil
+}
+
+func (s *Store) GetAmountProfile(ctx context.Context, userID string) (*domain.AmountProfile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.profiles[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+func (s *Store) SetAmountProfile(ctx context.Context, userID string, profile *domain.AmountProfile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles[userID] = *profile
+	return nil
+}
+
+func (s *Store) GetLastPosition(ctx context.Context, userID string) (*domain.GeoPosition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pos, ok := s.positions[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &pos, nil
+}
+
+func (s *Store) SetLastPosition(ctx context.Context, userID string, pos domain.GeoPosition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.positions[userID] = pos
+	return nil
+}
+
+func (s *Store) RecordIPUse(ctx context.Context, ip, userID, txID string, at time.Time, window time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ipUsers[ip] == nil {
+		s.ipUsers[ip] = make(map[string]time.Time)
+	}
+	s.ipUsers[ip][userID] = at
+
+	cutoff := at.Add(-window)
+	kept := s.ipTx[ip][:0]
+	for _, t := range s.ipTx[ip] {
+		if !t.Before(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	s.ipTx[ip] = append(kept, at)
+	return nil
+}
+
+func (s *Store) GetIPUsers(ctx context.Context, ip string, since time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var users []string
+	for u, at := range s.ipUsers[ip] {
+		if !at.Before(since) {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+func (s *Store) CountIPTransactions(ctx context.Context, ip string, since time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for _, t := range s.ipTx[ip] {
+		if !t.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *Store) FirstSeen(ctx context.Context, userID string, refs []domain.EntityRef) (time.Time, map[domain.EntityRef]time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen := make(map[domain.EntityRef]time.Time, len(refs))
+	for _, ref := range refs {
+		if at, ok := s.seen[userID][ref]; ok {
+			seen[ref] = at
+		}
+	}
+	return s.firstSeen[userID], seen, nil
+}
+
+func (s *Store) MarkSeen(ctx context.Context, userID string, refs []domain.EntityRef, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.firstSeen[userID]; !ok {
+		s.firstSeen[userID] = at
+	}
+	if s.seen[userID] == nil {
+		s.seen[userID] = make(map[domain.EntityRef]time.Time)
+	}
+	for _, ref := range refs {
+		if _, ok := s.seen[userID][ref]; !ok {
+			s.seen[userID][ref] = at
+		}
+	}
+	return nil
+}
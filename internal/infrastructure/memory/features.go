@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+// The memory store has no separate online tier: vectors are served from the
+// snapshot log.
+
+func (s *Store) PutFeatureVector(ctx context.Context, userID string, v domain.FeatureVector, retention time.Duration) error {
+	return nil
+}
+
+func (s *Store) GetFeatureVector(ctx context.Context, userID string, at time.Time) (*domain.FeatureVector, error) {
+	snap, err := s.GetFeatureSnapshot(ctx, userID, at)
+	if snap == nil || err != nil {
+		return nil, err
+	}
+	v := snap.Vector()
+	return &v, nil
+}
+
+func (s *Store) SaveFeatureSnapshot(ctx context.Context, snap *domain.FeatureSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.ContainsFunc(s.snapshots, func(e domain.FeatureSnapshot) bool { return e.TransactionID == snap.TransactionID }) {
+		return nil
+	}
+	snap.ID = uint(len(s.snapshots) + 1)
+	s.snapshots = append(s.snapshots, *snap)
+	return nil
+}
+
+func (s *Store) GetFeatureSnapshot(ctx context.Context, userID string, at time.Time) (*domain.FeatureSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found *domain.FeatureSnapshot
+	for i := range s.snapshots {
+		snap := &s.snapshots[i]
+		if snap.UserID == userID && !snap.At.After(at) && (found == nil || !snap.At.Before(found.At)) {
+			found = snap
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+	out := *found
+	return &out, nil
+}
+
+// FeatureSnapshots returns the snapshots in the order they were logged.
+func (s *Store) FeatureSnapshots() []domain.FeatureSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]domain.FeatureSnapshot(nil), s.snapshots...)
+}
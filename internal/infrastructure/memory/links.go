@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+func (s *Store) RecordLinks(ctx context.Context, userID string, refs []domain.EntityRef, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ref := range refs {
+		key := linkKey{userID: userID, ref: ref}
+		l, ok := s.links[key]
+		if !ok {
+			s.links[key] = domain.EntityLink{
+				UserID:      userID,
+				EntityType:  ref.Type,
+				EntityValue: ref.Value,
+				TxCount:     1,
+				FirstSeen:   at,
+				LastSeen:    at,
+			}
+			continue
+		}
+		l.TxCount++
+		if at.After(l.LastSeen) {
+			l.LastSeen = at
+		}
+		s.links[key] = l
+	}
+	return nil
+}
+
+func (s *Store) GetLinksByUsers(ctx context.Context, userIDs []string, since time.Time) ([]domain.EntityLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []domain.EntityLink
+	for key, l := range s.links {
+		if slices.Contains(userIDs, key.userID) && !l.LastSeen.Before(since) {
+			out = append(out, l)
+		}
+	}
+	sortLinks(out)
+	return out, nil
+}
+
+func (s *Store) GetLinksByEntities(ctx context.Context, refs []domain.EntityRef, since time.Time, limit int) ([]domain.EntityLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []domain.EntityLink
+	for key, l := range s.links {
+		if slices.Contains(refs, key.ref) && !l.LastSeen.Before(since) {
+			out = append(out, l)
+		}
+	}
+	sortLinks(out)
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// sortLinks orders links newest first, like the Postgres repository, and
+// breaks ties deterministically so replays are repeatable.
+func sortLinks(links []domain.EntityLink) {
+	slices.SortFunc(links, func(a, b domain.EntityLink) int {
+		if c := b.LastSeen.Compare(a.LastSeen); c != 0 {
+			return c
+		}
+		return cmp.Or(
+			strings.Compare(a.UserID, b.UserID),
+			strings.Compare(string(a.EntityType), string(b.EntityType)),
+			strings.Compare(a.EntityValue, b.EntityValue),
+		)
+	})
+}
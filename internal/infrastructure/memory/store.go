@@ -0,0 +1,136 @@
+// Package memory holds in-process implementations of the detector's
+// repositories. They back offline replays such as the backtest command, where
+// Redis and Postgres state must not be touched.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type Store struct {
+	mu        sync.Mutex
+	users     map[string]domain.User
+	events    []domain.FraudEvent
+	merchants map[string]domain.Merchant
+
+	activity  map[string][]domain.Activity
+	profiles  map[string]domain.AmountProfile
+	positions map[string]domain.GeoPosition
+	ipUsers   map[string]map[string]time.Time
+	ipTx      map[string][]time.Time
+	links     map[linkKey]domain.EntityLink
+	trusted   map[linkKey]domain.TrustedEntity
+	firstSeen map[string]time.Time
+	seen      map[string]map[domain.EntityRef]time.Time
+	near      map[string][]domain.Activity
+	amlCases  []domain.AMLCase
+	snapshots []domain.FeatureSnapshot
+}
+
+type linkKey struct {
+	userID string
+	ref    domain.EntityRef
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:     make(map[string]domain.User),
+		merchants: make(map[string]domain.Merchant),
+		activity:  make(map[string][]domain.Activity),
+		profiles:  make(map[string]domain.AmountProfile),
+		positions: make(map[string]domain.GeoPosition),
+		ipUsers:   make(map[string]map[string]time.Time),
+		ipTx:      make(map[string][]time.Time),
+		links:     make(map[linkKey]domain.EntityLink),
+		trusted:   make(map[linkKey]domain.TrustedEntity),
+		firstSeen: make(map[string]time.Time),
+		seen:      make(map[string]map[domain.EntityRef]time.Time),
+		near:      make(map[string][]domain.Activity),
+	}
+}
+
+func (s *Store) PutUser(u domain.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[u.ID] = u
+}
+
+func (s *Store) Events() []domain.FraudEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]domain.FraudEvent(nil), s.events...)
+}
+
+func (s *Store) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[id]
+	if !ok {
+		return nil, nil
+	}
+	return &u, nil
+}
+
+func (s *Store) CreateUser(ctx context.Context, user *domain.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[user.ID] = *user
+	return nil
+}
+
+func (s *Store) SaveFraudEvent(ctx context.Context, event *domain.FraudEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, *event)
+	return nil
+}
+
+func (s *Store) GetUserAmounts(ctx context.Context, userID string, limit int) ([]float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var amounts []float64
+	for i := len(s.events) - 1; i >= 0 && len(amounts) < limit; i-- {
+		if e := s.events[i]; e.UserID == userID && !e.IsBlocked {
+			amounts = append(amounts, e.Amount)
+		}
+	}
+	return amounts, nil
+}
+
+func (s *Store) GetMerchant(ctx context.Context, name string) (*domain.Merchant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.merchants[name]
+	if !ok {
+		return nil, nil
+	}
+	return &m, nil
+}
+
+func (s *Store) ListMerchants(ctx context.Context) ([]domain.Merchant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	merchants := make([]domain.Merchant, 0, len(s.merchants))
+	for _, m := range s.merchants {
+		merchants = append(merchants, m)
+	}
+	return merchants, nil
+}
+
+func (s *Store) UpsertMerchant(ctx context.Context, m *domain.Merchant) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.merchants[m.Name] = *m
+	return nil
+}
+
+func (s *Store) DeleteMerchant(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.merchants, name)
+	return nil
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+func (s *Store) GetTrustedEntities(ctx context.Context, userID string) ([]domain.TrustedEntity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []domain.TrustedEntity
+	for key, e := range s.trusted {
+		if key.userID == userID {
+			out = append(out, e)
+		}
+	}
+	slices.SortFunc(out, func(a, b domain.TrustedEntity) int {
+		return cmp.Or(
+			strings.Compare(string(a.EntityType), string(b.EntityType)),
+			strings.Compare(a.EntityValue, b.EntityValue),
+		)
+	})
+	return out, nil
+}
+
+func (s *Store) UpsertTrustedEntity(ctx context.Context, e *domain.TrustedEntity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := linkKey{userID: e.UserID, ref: e.Ref()}
+	if prev, ok := s.trusted[key]; ok {
+		e.CleanCount, e.LastSeen, e.CreatedAt = prev.CleanCount, prev.LastSeen, prev.CreatedAt
+	}
+	s.trusted[key] = *e
+	return nil
+}
+
+func (s *Store) DeleteTrustedEntity(ctx context.Context, userID string, ref domain.EntityRef) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := linkKey{userID: userID, ref: ref}
+	if _, ok := s.trusted[key]; !ok {
+		return fmt.Errorf("trusted %s %q: %w", ref.Type, ref.Value, domain.ErrNotFound)
+	}
+	delete(s.trusted, key)
+	return nil
+}
+
+func (s *Store) RecordCleanEntities(ctx context.Context, userID string, refs []domain.EntityRef, at, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ref := range refs {
+		key := linkKey{userID: userID, ref: ref}
+		e, ok := s.trusted[key]
+		if !ok {
+			e = domain.TrustedEntity{
+				UserID:      userID,
+				EntityType:  ref.Type,
+				EntityValue: ref.Value,
+				Source:      domain.TrustLearned,
+				CreatedAt:   at,
+			}
+		}
+		e.CleanCount++
+		if e.LastSeen == nil || at.After(*e.LastSeen) {
+			e.LastSeen = &at
+		}
+		if e.Source == domain.TrustLearned && (e.ExpiresAt == nil || expiresAt.After(*e.ExpiresAt)) {
+			e.ExpiresAt = &expiresAt
+		}
+		e.UpdatedAt = at
+		s.trusted[key] = e
+	}
+	return nil
+}
+
+func (s *Store) ForgetLearnedEntities(ctx context.Context, userID string, refs []domain.EntityRef) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ref := range refs {
+		key := linkKey{userID: userID, ref: ref}
+		if e, ok := s.trusted[key]; ok && e.Source == domain.TrustLearned {
+			delete(s.trusted, key)
+		}
+	}
+	return nil
+}
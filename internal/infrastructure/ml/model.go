@@ -0,0 +1,188 @@
+// Package ml scores transactions in-process with a logistic regression
+// model loaded from a JSON file, as an alternative to the remote risk engine.
+package ml
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+// Indicator features match when the lowercased merchant or location
+// contains the text after the prefix, e.g. "merchant:p2p".
+const (
+	merchantPrefix = "merchant:"
+	locationPrefix = "location:"
+)
+
+// Numeric features engineered from the transaction and the user profile.
+var numericFeatures = []string{
+	"amount_log",
+	"amount_to_avg",
+	"amount_to_max",
+	"user_risk",
+	"new_merchant",
+	"new_location",
+	"new_country",
+	"new_ip",
+	"account_age_log",
+	"night",
+}
+
+// maxRatio caps amount ratios so a first purchase against a tiny average
+// does not saturate the model on its own.
+const maxRatio = 50
+
+type ModelFile struct {
+	Name      string             `json:"name"`
+	Version   string             `json:"version"`
+	Intercept float64            `json:"intercept"`
+	Weights   map[string]float64 `json:"weights"`
+	// BlockProbability is the fraud probability at or above which the model
+	// reports the transaction as blocked.
+	BlockProbability float64 `json:"block_probability"`
+}
+
+type Model struct {
+	file ModelFile
+}
+
+func LoadModel(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read model file: %w", err)
+	}
+	var file ModelFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse model file %s: %w", path, err)
+	}
+	return NewModel(file)
+}
+
+func NewModel(file ModelFile) (*Model, error) {
+	if file.BlockProbability <= 0 || file.BlockProbability > 1 {
+		return nil, fmt.Errorf("model %s: block_probability %v must be in (0, 1]", file.Name, file.BlockProbability)
+	}
+	weights := make(map[string]float64, len(file.Weights))
+	for name, w := range file.Weights {
+		if !slices.Contains(numericFeatures, name) {
+			kw, ok := indicator(name)
+			if !ok || kw == "" {
+				return nil, fmt.Errorf("model %s: unknown feature %q", file.Name, name)
+			}
+			name = strings.ToLower(name)
+		}
+		weights[name] = w
+	}
+	file.Weights = weights
+	return &Model{file: file}, nil
+}
+
+func (m *Model) Name() string {
+	return m.file.Name + "@" + m.file.Version
+}
+
+// Analyze implements usecase.AIClient. It never fails, which is what makes
+// the model usable as a fallback for the remote engine.
+func (m *Model) Analyze(ctx context.Context, tx domain.Transaction, user domain.User) (domain.FraudAlert, error) {
+	features := Features(tx, user)
+	merchant, location := strings.ToLower(tx.Merchant), strings.ToLower(tx.Location)
+
+	type term struct {
+		name  string
+		value float64
+	}
+	z := m.file.Intercept
+	var terms []term
+	for name, w := range m.file.Weights {
+		value := features[name]
+		if kw, ok := strings.CutPrefix(name, merchantPrefix); ok && strings.Contains(merchant, kw) {
+			value = 1
+		}
+		if kw, ok := strings.CutPrefix(name, locationPrefix); ok && strings.Contains(location, kw) {
+			value = 1
+		}
+		if c := w * value; c != 0 {
+			z += c
+			terms = append(terms, term{name, c})
+		}
+	}
+	p := 1 / (1 + math.Exp(-z))
+
+	slices.SortFunc(terms, func(a, b term) int {
+		if c := cmp.Compare(b.value, a.value); c != 0 {
+			return c
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	var top []string
+	for _, t := range terms {
+		if t.value <= 0 || len(top) == 3 {
+			break
+		}
+		top = append(top, fmt.Sprintf("%s +%.2f", t.name, t.value))
+	}
+
+	reason := fmt.Sprintf("Local model %s: fraud probability %.2f", m.Name(), p)
+	if len(top) > 0 {
+		reason += " (" + strings.Join(top, ", ") + ")"
+	}
+	return domain.FraudAlert{
+		TransactionID: tx.ID,
+		Reason:        reason,
+		IsBlocked:     p >= m.file.BlockProbability,
+		RiskScore:     max(1, int(math.Round(p*100))),
+		Amount:        tx.Amount,
+		Location:      tx.Location,
+		Merchant:      tx.Merchant,
+	}, nil
+}
+
+// Features returns the numeric features of a transaction. Novelty features
+// are zero when the user's history is unknown.
+func Features(tx domain.Transaction, user domain.User) map[string]float64 {
+	f := map[string]float64{
+		"amount_log": math.Log1p(math.Max(tx.Amount, 0)),
+		"user_risk":  float64(user.RiskScore) / 100,
+	}
+	if user.AvgTx > 0 {
+		f["amount_to_avg"] = math.Min(tx.Amount/user.AvgTx, maxRatio)
+	}
+	if user.MaxTx > 0 {
+		f["amount_to_max"] = math.Min(tx.Amount/user.MaxTx, maxRatio)
+	}
+	if n := user.Novelty; n != nil {
+		f["new_merchant"] = flag(n.NewMerchant)
+		f["new_location"] = flag(n.NewLocation)
+		f["new_country"] = flag(n.NewCountry)
+		f["new_ip"] = flag(n.NewIP)
+		f["account_age_log"] = math.Log1p(float64(n.DaysSinceFirstSeen))
+	}
+	if !tx.Timestamp.IsZero() && tx.Timestamp.UTC().Hour() < 6 {
+		f["night"] = 1
+	}
+	return f
+}
+
+func indicator(name string) (string, bool) {
+	for _, prefix := range []string{merchantPrefix, locationPrefix} {
+		if kw, ok := strings.CutPrefix(name, prefix); ok {
+			return kw, true
+		}
+	}
+	return "", false
+}
+
+func flag(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+// FallbackAI asks the primary client and, when it fails, the fallback.
+type FallbackAI struct {
+	primary  AIClient
+	fallback AIClient
+}
+
+func NewFallbackAI(primary, fallback AIClient) *FallbackAI {
+	return &FallbackAI{primary: primary, fallback: fallback}
+}
+
+func (a *FallbackAI) Analyze(ctx context.Context, tx domain.Transaction, user domain.User) (domain.FraudAlert, error) {
+	alert, err := a.primary.Analyze(ctx, tx, user)
+	if err == nil {
+		return alert, nil
+	}
+	slog.Warn("Primary AI failed, using fallback", "tx_id", tx.ID, "err", err)
+
+	alert, fbErr := a.fallback.Analyze(ctx, tx, user)
+	if fbErr != nil {
+		return domain.FraudAlert{}, errors.Join(err, fbErr)
+	}
+	return alert, nil
+}
+
+type EnsembleMember struct {
+	Name   string
+	Client AIClient
+	Weight float64
+}
+
+// EnsembleAI averages the members' risk signals by weight. Members that
+// fail are left out and the rest reweighted; the ensemble fails only when
+// every member does. The transaction is blocked when the weighted signal
+// reaches 50, i.e. when members blocking it outweigh the others.
+type EnsembleAI struct {
+	members []EnsembleMember
+}
+
+func NewEnsembleAI(members ...EnsembleMember) (*EnsembleAI, error) {
+	if len(members) == 0 {
+		return nil, fmt.Errorf("%w: ensemble without members", domain.ErrInvalidInput)
+	}
+	for _, m := range members {
+		if m.Weight <= 0 {
+			return nil, fmt.Errorf("%w: ensemble member %s has weight %v", domain.ErrInvalidInput, m.Name, m.Weight)
+		}
+	}
+	return &EnsembleAI{members: members}, nil
+}
+
+func (a *EnsembleAI) Analyze(ctx context.Context, tx domain.Transaction, user domain.User) (domain.FraudAlert, error) {
+	var total, weights float64
+	var reasons []string
+	var errs []error
+	var pushMsg string
+	for _, m := range a.members {
+		alert, err := m.Client.Analyze(ctx, tx, user)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.Name, err))
+			continue
+		}
+		signal := aiSignal(alert)
+		total += m.Weight * signal
+		weights += m.Weight
+		reasons = append(reasons, fmt.Sprintf("%s %.0f: %s", m.Name, signal, alert.Reason))
+		if pushMsg == "" {
+			pushMsg = alert.AIPushMessage
+		}
+	}
+	if weights == 0 {
+		return domain.FraudAlert{}, errors.Join(errs...)
+	}
+	if len(errs) > 0 {
+		slog.Warn("Ensemble member failed", "tx_id", tx.ID, "err", errors.Join(errs...))
+	}
+
+	score := total / weights
+	return domain.FraudAlert{
+		TransactionID: tx.ID,
+		Reason:        strings.Join(reasons, " | "),
+		AIPushMessage: pushMsg,
+		IsBlocked:     score >= 50,
+		RiskScore:     int(math.Round(score)),
+		Amount:        tx.Amount,
+		Location:      tx.Location,
+		Merchant:      tx.Merchant,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type scoredAI struct {
+	score int
+}
+
+func (m scoredAI) Analyze(ctx context.Context, tx domain.Transaction, user domain.User) (domain.FraudAlert, error) {
+	return domain.FraudAlert{RiskScore: m.score, Reason: "local model"}, nil
+}
+
+func TestFallbackAI_UsesFallbackWhenPrimaryFails(t *testing.T) {
+	primary := &mockAI{fail: true}
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(NewFallbackAI(primary, scoredAI{score: 90}), &mockRepo{}, &mockCache{}, publisher)
+
+	if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-1", UserID: "user-1", Amount: 50}); err != nil {
+		t.Fatal(err)
+	}
+
+	if primary.calls != 1 {
+		t.Errorf("Expected the primary to be tried once, got %d calls", primary.calls)
+	}
+	result := publisher.PublishedAlert
+	if result.Decision == domain.DecisionAllow {
+		t.Errorf("Expected the fallback's high score to escalate, got %s (score %d)", result.Decision, result.RiskScore)
+	}
+	if last := result.Reasons[len(result.Reasons)-1]; last.Code != "AI_VERDICT" || last.Message != "local model" {
+		t.Errorf("Expected the fallback verdict instead of AI_UNAVAILABLE, got %+v", last)
+	}
+}
+
+func TestEnsembleAI_WeightsMembersAndSkipsFailures(t *testing.T) {
+	ensemble, err := NewEnsembleAI(
+		EnsembleMember{Name: "remote", Client: &mockAI{blocked: true}, Weight: 0.7},
+		EnsembleMember{Name: "local", Client: scoredAI{score: 20}, Weight: 0.3},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alert, err := ensemble.Analyze(context.Background(), domain.Transaction{ID: "tx-1"}, domain.User{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alert.RiskScore != 76 || !alert.IsBlocked {
+		t.Errorf("Expected weighted score 76 and a block, got %d blocked=%t", alert.RiskScore, alert.IsBlocked)
+	}
+
+	ensemble, _ = NewEnsembleAI(
+		EnsembleMember{Name: "remote", Client: &mockAI{fail: true}, Weight: 0.7},
+		EnsembleMember{Name: "local", Client: scoredAI{score: 20}, Weight: 0.3},
+	)
+	alert, err = ensemble.Analyze(context.Background(), domain.Transaction{ID: "tx-2"}, domain.User{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alert.RiskScore != 20 || alert.IsBlocked {
+		t.Errorf("Expected the surviving member's score, got %d blocked=%t", alert.RiskScore, alert.IsBlocked)
+	}
+
+	ensemble, _ = NewEnsembleAI(EnsembleMember{Name: "remote", Client: &mockAI{fail: true}, Weight: 1})
+	if _, err := ensemble.Analyze(context.Background(), domain.Transaction{ID: "tx-3"}, domain.User{}); err == nil {
+		t.Error("Expected an error when every member fails")
+	}
+}
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+const SourceStructuring = "structuring"
+
+// StructuringStore keeps each user's near-threshold transactions for the
+// structuring period.
+type StructuringStore interface {
+	RecordNearThreshold(ctx context.Context, userID string, activity domain.Activity, period time.Duration) error
+	GetNearThreshold(ctx context.Context, userIDs []string, since time.Time) (map[string][]domain.Activity, error)
+}
+
+type AMLCaseRepository interface {
+	SaveAMLCase(ctx context.Context, c *domain.AMLCase) error
+	ListAMLCases(ctx context.Context, status domain.AMLCaseStatus, limit int) ([]domain.AMLCase, error)
+}
+
+type StructuringConfig struct {
+	// Thresholds are the reporting thresholds, in the base currency.
+	Thresholds []float64
+	// Margin is how far below a threshold, as a fraction of it, an amount
+	// still counts as near it.
+	Margin float64
+	Period time.Duration
+	// MinCount is the number of near-threshold transactions of the user,
+	// the current one included, that indicate structuring.
+	MinCount int
+	// MinLinkedCount is the same count taken across the user and the
+	// accounts linked to it.
+	MinLinkedCount int
+}
+
+func DefaultStructuringConfig() StructuringConfig {
+	return StructuringConfig{
+		Thresholds:     []float64{10000},
+		Margin:         0.1,
+		Period:         7 * 24 * time.Hour,
+		MinCount:       3,
+		MinLinkedCount: 5,
+	}
+}
+
+// threshold returns the lowest threshold the amount is just under, or 0.
+func (c StructuringConfig) threshold(amount float64) float64 {
+	var found float64
+	for _, t := range c.Thresholds {
+		if amount < t && amount >= t*(1-c.Margin) && (found == 0 || t < found) {
+			found = t
+		}
+	}
+	return found
+}
+
+// StructuringDetector flags repeated amounts just under a reporting
+// threshold within the period, by the user alone or together with linked
+// accounts. A hit forces a review and, once the transaction is decided,
+// opens an AML case.
+type StructuringDetector struct {
+	store StructuringStore
+	cases AMLCaseRepository
+	graph *LinkGraph
+	cfg   StructuringConfig
+
+	mu      sync.Mutex
+	pending map[string]*domain.AMLCase
+}
+
+// NewStructuringDetector builds the detector; graph may be nil to count the
+// user's own transactions only, and cases may be nil to flag without opening
+// cases, as a shadow detector should.
+func NewStructuringDetector(store StructuringStore, cases AMLCaseRepository, graph *LinkGraph, cfg StructuringConfig) *StructuringDetector {
+	return &StructuringDetector{store: store, cases: cases, graph: graph, cfg: cfg, pending: make(map[string]*domain.AMLCase)}
+}
+
+func (d *StructuringDetector) Name() string {
+	return SourceStructuring
+}
+
+func (d *StructuringDetector) Evaluate(ctx context.Context, tx domain.Transaction, _ domain.User, facts Facts) ([]Finding, error) {
+	threshold := d.cfg.threshold(tx.Amount)
+	facts["features.structuring_threshold"] = threshold
+	if threshold == 0 {
+		facts["features.structuring_count"] = 0
+		facts["features.structuring_linked_count"] = 0
+		return nil, nil
+	}
+
+	now := txTime(tx)
+	users := []string{tx.UserID}
+	if d.graph != nil {
+		report, err := d.graph.component(ctx, tx.UserID, txEntities(tx), now)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range report.Linked {
+			users = append(users, u.UserID)
+		}
+	}
+
+	since := now.Add(-d.cfg.Period)
+	stored, err := d.store.GetNearThreshold(ctx, users, since)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &domain.AMLCase{
+		TransactionID:    tx.ID,
+		UserID:           tx.UserID,
+		Typology:         domain.AMLTypologyStructuring,
+		Threshold:        threshold,
+		Currency:         tx.Currency,
+		TransactionCount: 1,
+		TotalAmount:      tx.Amount,
+		TransactionIDs:   []string{tx.ID},
+		PeriodStart:      since,
+		Status:           domain.AMLCaseOpen,
+	}
+	own, linked := 1, 0
+	for _, userID := range users {
+		counted := false
+		for _, a := range stored[userID] {
+			if a.TransactionID == tx.ID || a.At.After(now) || d.cfg.threshold(a.Amount) != threshold {
+				continue
+			}
+			if userID == tx.UserID {
+				own++
+			} else {
+				linked++
+				counted = true
+			}
+			c.TransactionCount++
+			c.TotalAmount += a.Amount
+			c.TransactionIDs = append(c.TransactionIDs, a.TransactionID)
+		}
+		if counted {
+			c.LinkedUsers = append(c.LinkedUsers, userID)
+		}
+	}
+
+	facts["features.structuring_count"] = own
+	facts["features.structuring_linked_count"] = linked
+
+	if own < d.cfg.MinCount && own+linked < d.cfg.MinLinkedCount {
+		return nil, nil
+	}
+
+	message := fmt.Sprintf("%d transactions just under %.2f %s within %s, totalling %.2f",
+		own, threshold, tx.Currency, d.cfg.Period, c.TotalAmount)
+	if len(c.LinkedUsers) > 0 {
+		slices.Sort(c.LinkedUsers)
+		message = fmt.Sprintf("%d transactions just under %.2f %s within %s, %d of them by linked accounts %s, totalling %.2f",
+			own+linked, threshold, tx.Currency, d.cfg.Period, linked, strings.Join(c.LinkedUsers, ", "), c.TotalAmount)
+	}
+	d.mu.Lock()
+	d.pending[tx.ID] = c
+	d.mu.Unlock()
+
+	return []Finding{{
+		Detector: SourceStructuring,
+		Code:     "STRUCTURING",
+		Score:    clamp(float64(own+linked)*20, 0, 100),
+		Floor:    domain.DecisionReview,
+		Message:  "Possible structuring: " + message,
+	}}, nil
+}
+
+// Record opens the AML case found by Evaluate and keeps near-threshold
+// transactions that went through; blocked ones moved no money.
+func (d *StructuringDetector) Record(ctx context.Context, tx domain.Transaction, decision domain.Decision) error {
+	d.mu.Lock()
+	c := d.pending[tx.ID]
+	delete(d.pending, tx.ID)
+	d.mu.Unlock()
+	if c != nil && d.cases != nil {
+		if err := d.cases.SaveAMLCase(ctx, c); err != nil {
+			slog.Error("Failed to save AML case", "tx_id", tx.ID, "user_id", tx.UserID, "err", err)
+		}
+	}
+
+	if decision == domain.DecisionBlock || d.cfg.threshold(tx.Amount) == 0 {
+		return nil
+	}
+	return d.store.RecordNearThreshold(ctx, tx.UserID, domain.Activity{
+		TransactionID: tx.ID,
+		Amount:        tx.Amount,
+		Merchant:      tx.Merchant,
+		Location:      tx.Location,
+		IP:            tx.IP,
+		At:            txTime(tx),
+	}, d.cfg.Period)
+}
+
+// AMLService is the compliance view over AML cases.
+type AMLService struct {
+	repo AMLCaseRepository
+}
+
+func NewAMLService(repo AMLCaseRepository) *AMLService {
+	return &AMLService{repo: repo}
+}
+
+func (s *AMLService) Cases(ctx context.Context, status domain.AMLCaseStatus, limit int) ([]domain.AMLCase, error) {
+	switch status {
+	case "", domain.AMLCaseOpen, domain.AMLCaseReported, domain.AMLCaseClosed:
+	default:
+		return nil, fmt.Errorf("%w: unknown case status %q", domain.ErrInvalidInput, status)
+	}
+	return s.repo.ListAMLCases(ctx, status, limit)
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type memoryAML struct {
+	near  map[string][]domain.Activity
+	cases []domain.AMLCase
+}
+
+func (m *memoryAML) RecordNearThreshold(ctx context.Context, userID string, activity domain.Activity, period time.Duration) error {
+	if m.near == nil {
+		m.near = make(map[string][]domain.Activity)
+	}
+	m.near[userID] = append(m.near[userID], activity)
+	return nil
+}
+
+func (m *memoryAML) GetNearThreshold(ctx context.Context, userIDs []string, since time.Time) (map[string][]domain.Activity, error) {
+	out := make(map[string][]domain.Activity)
+	for _, id := range userIDs {
+		for _, a := range m.near[id] {
+			if !a.At.Before(since) {
+				out[id] = append(out[id], a)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryAML) SaveAMLCase(ctx context.Context, c *domain.AMLCase) error {
+	m.cases = append(m.cases, *c)
+	return nil
+}
+
+func (m *memoryAML) ListAMLCases(ctx context.Context, status domain.AMLCaseStatus, limit int) ([]domain.AMLCase, error) {
+	return m.cases, nil
+}
+
+func TestStructuringDetector_RepeatedAmountsUnderThreshold(t *testing.T) {
+	now := time.Now()
+	store := &memoryAML{}
+	d := NewStructuringDetector(store, store, nil, DefaultStructuringConfig())
+
+	for i, amount := range []float64{9800, 9500} {
+		tx := domain.Transaction{ID: fmt.Sprintf("p2p-%d", i), UserID: "user-2", Amount: amount, Timestamp: now.Add(time.Duration(i-2) * 24 * time.Hour)}
+		if err := d.Record(context.Background(), tx, domain.DecisionAllow); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = d.Record(context.Background(), domain.Transaction{ID: "regular", UserID: "user-2", Amount: 4000, Timestamp: now.Add(-time.Hour)}, domain.DecisionAllow)
+
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(&mockAI{}, &mockRepo{}, &mockCache{}, publisher, WithDetectors(d))
+	if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-1", UserID: "user-2", Amount: 9900, Currency: "USD", Timestamp: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	result := publisher.PublishedAlert
+	if result.Decision != domain.DecisionReview {
+		t.Errorf("Expected structuring to force REVIEW, got %s (score %d)", result.Decision, result.RiskScore)
+	}
+	if len(result.Reasons) == 0 || result.Reasons[0].Code != "STRUCTURING" {
+		t.Errorf("Expected STRUCTURING reason, got %+v", result.Reasons)
+	}
+	if len(store.cases) != 1 {
+		t.Fatalf("Expected one AML case, got %d", len(store.cases))
+	}
+	if c := store.cases[0]; c.TransactionCount != 3 || c.TotalAmount != 29200 || c.Threshold != 10000 {
+		t.Errorf("Unexpected AML case: %+v", c)
+	}
+}
+
+func TestStructuringDetector_CountsLinkedAccounts(t *testing.T) {
+	now := time.Now()
+	links := &memoryLinkRepo{}
+	ip := domain.EntityRef{Type: domain.EntityIP, Value: "198.51.100.9"}
+	store := &memoryAML{}
+	for i, user := range []string{"mule-1", "mule-2", "mule-3"} {
+		_ = links.RecordLinks(context.Background(), user, []domain.EntityRef{ip}, now.Add(-time.Hour))
+		_ = store.RecordNearThreshold(context.Background(), user, domain.Activity{
+			TransactionID: fmt.Sprintf("mule-tx-%d", i), Amount: 9700, At: now.Add(-time.Hour),
+		}, 0)
+	}
+	_ = store.RecordNearThreshold(context.Background(), "user-2", domain.Activity{TransactionID: "own-1", Amount: 9300, At: now.Add(-time.Hour)}, 0)
+
+	d := NewStructuringDetector(store, store, NewLinkGraph(links, DefaultLinkGraphConfig()), DefaultStructuringConfig())
+	facts := Facts{}
+	tx := domain.Transaction{ID: "tx-2", UserID: "user-2", Amount: 9950, IP: ip.Value, Timestamp: now}
+	findings, err := d.Evaluate(context.Background(), tx, domain.User{}, facts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.cases) != 0 {
+		t.Fatalf("Expected no AML case before the transaction is decided, got %+v", store.cases)
+	}
+	if err := d.Record(context.Background(), tx, domain.DecisionReview); err != nil {
+		t.Fatal(err)
+	}
+
+	if facts["features.structuring_count"] != 2 || facts["features.structuring_linked_count"] != 3 {
+		t.Errorf("Unexpected structuring features: %v", facts)
+	}
+	if len(findings) != 1 || findings[0].Floor != domain.DecisionReview {
+		t.Fatalf("Expected a REVIEW finding across linked accounts, got %+v", findings)
+	}
+	if len(store.cases) != 1 || len(store.cases[0].LinkedUsers) != 3 {
+		t.Errorf("Expected the case to name the linked accounts, got %+v", store.cases)
+	}
+}
+
+func TestStructuringDetector_IgnoresAmountsAwayFromThreshold(t *testing.T) {
+	store := &memoryAML{}
+	d := NewStructuringDetector(store, store, nil, DefaultStructuringConfig())
+
+	for _, amount := range []float64{8999, 10000, 12000} {
+		tx := domain.Transaction{ID: "tx", UserID: "user-2", Amount: amount, Timestamp: time.Now()}
+		findings, err := d.Evaluate(context.Background(), tx, domain.User{}, Facts{})
+		if err != nil || len(findings) != 0 {
+			t.Errorf("Amount %.2f: expected no finding, got %+v (err %v)", amount, findings, err)
+		}
+		_ = d.Record(context.Background(), tx, domain.DecisionAllow)
+	}
+	if len(store.near) != 0 {
+		t.Errorf("Expected nothing recorded, got %v", store.near)
+	}
+}
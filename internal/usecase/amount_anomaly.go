@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+const SourceAmountAnomaly = "amount_anomaly"
+
+type AmountAnomalyConfig struct {
+	MinSamples      int64
+	ZScoreThreshold float64
+	PointsPerSigma  float64
+}
+
+func DefaultAmountAnomalyConfig() AmountAnomalyConfig {
+	return AmountAnomalyConfig{
+		MinSamples:      5,
+		ZScoreThreshold: 3,
+		PointsPerSigma:  20,
+	}
+}
+
+func amountFeatures(profile *domain.AmountProfile, amount float64, facts Facts) {
+	facts["features.amount_count"] = profile.Count
+	facts["features.amount_zscore"] = profile.ZScore(amount)
+	facts["features.amount_percentile"] = profile.Rank(amount) * 100
+	facts["features.amount_p95"] = profile.Percentile(0.95)
+	facts["features.amount_p99"] = profile.Percentile(0.99)
+}
+
+func scoreAmountAnomaly(cfg AmountAnomalyConfig, profile *domain.AmountProfile, amount float64) *Finding {
+	if profile.Count < cfg.MinSamples {
+		return nil
+	}
+	z := profile.ZScore(amount)
+	if z < cfg.ZScoreThreshold {
+		return nil
+	}
+
+	return &Finding{
+		Detector: SourceAmountAnomaly,
+		Code:     "AMOUNT_OUTLIER",
+		Score:    clamp(z*cfg.PointsPerSigma, 0, 100),
+		Message: fmt.Sprintf("Amount %.2f is %.1fσ above the user's mean %.2f (p99 %.2f, n=%d)",
+			amount, z, profile.Mean, profile.Percentile(0.99), profile.Count),
+	}
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+// ReplayRecord is a historical transaction with the outcome stored for it.
+// It is also the line format of NDJSON exports.
+type ReplayRecord struct {
+	domain.Transaction
+	Decision    domain.Decision `json:"decision"`
+	RiskScore   int             `json:"risk_score"`
+	AIBlocked   bool            `json:"ai_blocked"`
+	AIRiskScore int             `json:"ai_risk_score"`
+}
+
+// ReplayRecordFromEvent rebuilds the transaction as it arrived, in its
+// original currency when the event recorded one.
+func ReplayRecordFromEvent(e domain.FraudEvent) ReplayRecord {
+	amount, currency := e.Amount, e.Currency
+	if e.OriginalCurrency != "" {
+		amount, currency = e.OriginalAmount, e.OriginalCurrency
+	}
+	decision := e.Decision
+	if decision == "" {
+		decision = domain.DecisionAllow
+		if e.IsBlocked {
+			decision = domain.DecisionBlock
+		}
+	}
+	return ReplayRecord{
+		Transaction: domain.Transaction{
+			ID:        e.TransactionID,
+			UserID:    e.UserID,
+			Amount:    amount,
+			Currency:  currency,
+			Merchant:  e.Merchant,
+			Location:  e.Location,
+			IP:        e.IP,
+			DeviceID:  e.DeviceID,
+			Timestamp: e.CreatedAt,
+		},
+		Decision:    decision,
+		RiskScore:   e.RiskScore,
+		AIBlocked:   e.AIBlocked,
+		AIRiskScore: e.AIRiskScore,
+	}
+}
+
+type ReplayAIMode string
+
+const (
+	ReplayAIRecorded ReplayAIMode = "recorded"
+	ReplayAIAllow    ReplayAIMode = "allow"
+	ReplayAIFail     ReplayAIMode = "fail"
+)
+
+// ReplayAI stands in for the risk engine during a replay: it returns the
+// verdict stored with the record, a blanket allow, or an outage.
+type ReplayAI struct {
+	mode    ReplayAIMode
+	current ReplayRecord
+}
+
+func NewReplayAI(mode ReplayAIMode) (*ReplayAI, error) {
+	switch mode {
+	case ReplayAIRecorded, ReplayAIAllow, ReplayAIFail:
+		return &ReplayAI{mode: mode}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown replay AI mode %q", domain.ErrInvalidInput, mode)
+}
+
+func (a *ReplayAI) Analyze(ctx context.Context, tx domain.Transaction, user domain.User) (domain.FraudAlert, error) {
+	switch a.mode {
+	case ReplayAIFail:
+		return domain.FraudAlert{}, errors.New("replay: ai disabled")
+	case ReplayAIAllow:
+		return domain.FraudAlert{TransactionID: tx.ID, Reason: "Replay: AI allow"}, nil
+	}
+	return domain.FraudAlert{
+		TransactionID: tx.ID,
+		IsBlocked:     a.current.AIBlocked,
+		RiskScore:     a.current.AIRiskScore,
+		Reason:        "Replay: recorded AI verdict",
+	}, nil
+}
+
+type AlertRecorder struct {
+	last domain.FraudAlert
+}
+
+func (r *AlertRecorder) Publish(ctx context.Context, alert domain.FraudAlert) error {
+	r.last = alert
+	return nil
+}
+
+type BacktestDiff struct {
+	TransactionID string
+	UserID        string
+	Amount        float64
+	Currency      string
+	Stored        domain.Decision
+	Replayed      domain.Decision
+	StoredScore   int
+	ReplayedScore int
+	Reason        string
+}
+
+type BacktestReport struct {
+	Total       int
+	Warmup      int
+	Changed     int
+	Stored      map[domain.Decision]int
+	Replayed    map[domain.Decision]int
+	Transitions map[[2]domain.Decision]int
+	ScoreDelta  int
+	Diffs       []BacktestDiff
+	From, To    time.Time
+}
+
+func (r *BacktestReport) AvgScoreDelta() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.ScoreDelta) / float64(r.Total)
+}
+
+// Backtester replays records in timestamp order through a detector built on
+// in-memory stores, with ai and alerts wired in as its AI client and
+// publisher. Records before since only warm up profiles and windows.
+type Backtester struct {
+	detector *FraudDetector
+	ai       *ReplayAI
+	alerts   *AlertRecorder
+	since    time.Time
+	maxDiffs int
+}
+
+func NewBacktester(detector *FraudDetector, ai *ReplayAI, alerts *AlertRecorder, since time.Time, maxDiffs int) *Backtester {
+	return &Backtester{detector: detector, ai: ai, alerts: alerts, since: since, maxDiffs: maxDiffs}
+}
+
+func (b *Backtester) Run(ctx context.Context, records []ReplayRecord) (*BacktestReport, error) {
+	slices.SortStableFunc(records, func(x, y ReplayRecord) int {
+		return x.Timestamp.Compare(y.Timestamp)
+	})
+
+	report := &BacktestReport{
+		Stored:      make(map[domain.Decision]int),
+		Replayed:    make(map[domain.Decision]int),
+		Transitions: make(map[[2]domain.Decision]int),
+	}
+	for _, rec := range records {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		b.ai.current = rec
+		b.alerts.last = domain.FraudAlert{}
+		if err := b.detector.Detect(ctx, rec.Transaction); err != nil {
+			return report, fmt.Errorf("replay %s: %w", rec.ID, err)
+		}
+		if rec.Timestamp.Before(b.since) {
+			report.Warmup++
+			continue
+		}
+
+		got := b.alerts.last
+		if report.Total == 0 {
+			report.From = rec.Timestamp
+		}
+		report.To = rec.Timestamp
+		report.Total++
+		report.Stored[rec.Decision]++
+		report.Replayed[got.Decision]++
+		report.ScoreDelta += got.RiskScore - rec.RiskScore
+		if got.Decision == rec.Decision {
+			continue
+		}
+
+		report.Changed++
+		report.Transitions[[2]domain.Decision{rec.Decision, got.Decision}]++
+		if len(report.Diffs) < b.maxDiffs {
+			report.Diffs = append(report.Diffs, BacktestDiff{
+				TransactionID: rec.ID,
+				UserID:        rec.UserID,
+				Amount:        rec.Amount,
+				Currency:      rec.Currency,
+				Stored:        rec.Decision,
+				Replayed:      got.Decision,
+				StoredScore:   rec.RiskScore,
+				ReplayedScore: got.RiskScore,
+				Reason:        got.Reason,
+			})
+		}
+	}
+	return report, nil
+}
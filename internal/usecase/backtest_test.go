@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+func TestBacktester_ReportsChangedDecisions(t *testing.T) {
+	since := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	record := func(id string, at time.Time, aiScore int) ReplayRecord {
+		return ReplayRecord{
+			Transaction: domain.Transaction{ID: id, UserID: "user-1", Amount: 100, Merchant: "Amazon", Timestamp: at},
+			Decision:    domain.DecisionAllow,
+			AIBlocked:   aiScore >= 90,
+			AIRiskScore: aiScore,
+		}
+	}
+	records := []ReplayRecord{
+		record("tx-3", since.Add(2*time.Hour), 95),
+		record("tx-2", since.Add(time.Hour), 10),
+		record("tx-1", since.Add(-time.Hour), 10),
+	}
+
+	ai, err := NewReplayAI(ReplayAIRecorded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alerts := &AlertRecorder{}
+	detector := NewFraudDetector(ai, &mockRepo{}, &mockCache{}, alerts)
+
+	report, err := NewBacktester(detector, ai, alerts, since, 10).Run(context.Background(), records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Warmup != 1 || report.Total != 2 {
+		t.Fatalf("Expected 1 warm-up and 2 evaluated records, got %d and %d", report.Warmup, report.Total)
+	}
+	if report.Changed != 1 || len(report.Diffs) != 1 {
+		t.Fatalf("Expected one changed decision, got %d (%+v)", report.Changed, report.Diffs)
+	}
+	if d := report.Diffs[0]; d.TransactionID != "tx-3" || d.Replayed == domain.DecisionAllow {
+		t.Errorf("Expected tx-3 to escalate from ALLOW, got %+v", d)
+	}
+}
+
+func TestReplayAI_RejectsUnknownMode(t *testing.T) {
+	if _, err := NewReplayAI("random"); err == nil {
+		t.Error("Expected error for unknown replay AI mode")
+	}
+}
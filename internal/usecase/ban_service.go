@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+const AutoBanActor = "system:auto-ban"
+
+type BanRepository interface {
+	SetUserBan(ctx context.Context, userID string, banned bool, actor, reason string) error
+	CountBlockedEvents(ctx context.Context, userID string, since time.Time) (int64, error)
+	GetBanAudit(ctx context.Context, userID string) ([]domain.BanAudit, error)
+}
+
+// AutoBanPolicy bans a user once MaxBlocks transactions were blocked within
+// Window. A non-positive MaxBlocks disables automatic banning.
+type AutoBanPolicy struct {
+	MaxBlocks int
+	Window    time.Duration
+}
+
+type BanService struct {
+	repo   BanRepository
+	policy AutoBanPolicy
+	cache  *RiskCache
+}
+
+// NewBanService creates the service; cache may be nil. When set, cached AI
+// verdicts of a user are dropped whenever the user is banned or cleared.
+func NewBanService(repo BanRepository, policy AutoBanPolicy, cache *RiskCache) *BanService {
+	return &BanService{repo: repo, policy: policy, cache: cache}
+}
+
+func (s *BanService) Ban(ctx context.Context, userID, actor, reason string) error {
+	if strings.TrimSpace(actor) == "" {
+		return fmt.Errorf("%w: actor is required", domain.ErrInvalidInput)
+	}
+	if err := s.repo.SetUserBan(ctx, userID, true, actor, reason); err != nil {
+		return err
+	}
+	slog.Warn("User banned", "user_id", userID, "actor", actor, "reason", reason)
+	s.invalidate(ctx, userID)
+	return nil
+}
+
+func (s *BanService) Unban(ctx context.Context, userID, actor, reason string) error {
+	if strings.TrimSpace(actor) == "" {
+		return fmt.Errorf("%w: actor is required", domain.ErrInvalidInput)
+	}
+	if err := s.repo.SetUserBan(ctx, userID, false, actor, reason); err != nil {
+		return err
+	}
+	slog.Info("User unbanned", "user_id", userID, "actor", actor, "reason", reason)
+	s.invalidate(ctx, userID)
+	return nil
+}
+
+func (s *BanService) invalidate(ctx context.Context, userID string) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.InvalidateUser(ctx, userID); err != nil {
+		slog.Warn("Failed to invalidate risk cache", "user_id", userID, "err", err)
+	}
+}
+
+func (s *BanService) History(ctx context.Context, userID string) ([]domain.BanAudit, error) {
+	return s.repo.GetBanAudit(ctx, userID)
+}
+
+// EvaluateAutoBan is called after a blocked transaction has been persisted
+// and bans the user when the policy threshold is reached. Blocks from before
+// the user's last unban don't count, so an analyst's unban sticks.
+func (s *BanService) EvaluateAutoBan(ctx context.Context, userID string) (bool, error) {
+	if s.policy.MaxBlocks <= 0 {
+		return false, nil
+	}
+
+	since := time.Now().Add(-s.policy.Window)
+	audit, err := s.repo.GetBanAudit(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, a := range audit {
+		if a.Action == domain.BanActionUnban {
+			if a.CreatedAt.After(since) {
+				since = a.CreatedAt
+			}
+			break
+		}
+	}
+
+	blocked, err := s.repo.CountBlockedEvents(ctx, userID, since)
+	if err != nil {
+		return false, err
+	}
+	if blocked < int64(s.policy.MaxBlocks) {
+		return false, nil
+	}
+
+	reason := fmt.Sprintf("%d blocked transactions within %s", blocked, s.policy.Window)
+	return true, s.Ban(ctx, userID, AutoBanActor, reason)
+}
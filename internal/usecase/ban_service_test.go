@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type mockBanRepo struct {
+	blocks []time.Time
+	banned map[string]bool
+	audit  []domain.BanAudit
+}
+
+func (m *mockBanRepo) block(n int) {
+	for range n {
+		m.blocks = append(m.blocks, time.Now())
+	}
+}
+
+func (m *mockBanRepo) SetUserBan(ctx context.Context, userID string, banned bool, actor, reason string) error {
+	m.banned[userID] = banned
+	action := domain.BanActionUnban
+	if banned {
+		action = domain.BanActionBan
+	}
+	m.audit = append(m.audit, domain.BanAudit{UserID: userID, Action: action, Actor: actor, Reason: reason, CreatedAt: time.Now()})
+	return nil
+}
+
+func (m *mockBanRepo) CountBlockedEvents(ctx context.Context, userID string, since time.Time) (int64, error) {
+	var count int64
+	for _, at := range m.blocks {
+		if !at.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockBanRepo) GetBanAudit(ctx context.Context, userID string) ([]domain.BanAudit, error) {
+	audit := slices.Clone(m.audit)
+	slices.Reverse(audit)
+	return audit, nil
+}
+
+func TestBanService_AutoBanAfterThreshold(t *testing.T) {
+	repo := &mockBanRepo{banned: map[string]bool{}}
+	repo.block(2)
+	svc := NewBanService(repo, AutoBanPolicy{MaxBlocks: 3, Window: time.Hour}, nil)
+
+	if banned, _ := svc.EvaluateAutoBan(context.Background(), "user-2"); banned {
+		t.Fatalf("Expected no ban below threshold")
+	}
+
+	repo.block(1)
+	banned, err := svc.EvaluateAutoBan(context.Background(), "user-2")
+	if err != nil || !banned {
+		t.Fatalf("Expected auto-ban at threshold, got banned=%v err=%v", banned, err)
+	}
+	if len(repo.audit) != 1 || repo.audit[0].Actor != AutoBanActor {
+		t.Errorf("Expected audit entry by %s, got: %+v", AutoBanActor, repo.audit)
+	}
+}
+
+func TestBanService_AutoBanIgnoresBlocksBeforeUnban(t *testing.T) {
+	repo := &mockBanRepo{banned: map[string]bool{}}
+	svc := NewBanService(repo, AutoBanPolicy{MaxBlocks: 3, Window: time.Hour}, nil)
+	ctx := context.Background()
+
+	repo.block(3)
+	if banned, err := svc.EvaluateAutoBan(ctx, "user-2"); err != nil || !banned {
+		t.Fatalf("Expected auto-ban at threshold, got banned=%v err=%v", banned, err)
+	}
+	time.Sleep(time.Millisecond)
+	if err := svc.Unban(ctx, "user-2", "analyst", "false positives"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+
+	repo.block(1)
+	if banned, err := svc.EvaluateAutoBan(ctx, "user-2"); err != nil || banned {
+		t.Errorf("Expected one block after an unban not to re-ban, got banned=%v err=%v", banned, err)
+	}
+	repo.block(2)
+	if banned, _ := svc.EvaluateAutoBan(ctx, "user-2"); !banned {
+		t.Errorf("Expected a fresh run of blocks after the unban to ban again")
+	}
+}
+
+func TestBanService_RequiresActor(t *testing.T) {
+	svc := NewBanService(&mockBanRepo{banned: map[string]bool{}}, AutoBanPolicy{}, nil)
+
+	if err := svc.Unban(context.Background(), "user-2", " ", "cleared"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput without actor, got: %v", err)
+	}
+}
+
+func TestBanService_UnbanDropsCachedVerdicts(t *testing.T) {
+	store := newMemoryRiskCache()
+	cache, _ := NewRiskCache(store, DefaultRiskCacheConfig())
+	svc := NewBanService(&mockBanRepo{banned: map[string]bool{}}, AutoBanPolicy{}, cache)
+
+	if err := svc.Unban(context.Background(), "user-2", "analyst", "cleared"); err != nil {
+		t.Fatal(err)
+	}
+	if store.gens["user:user-2"] != 1 {
+		t.Errorf("Expected unban to invalidate the user's cached verdicts")
+	}
+}
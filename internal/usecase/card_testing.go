@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+const SourceCardTesting = "card_testing"
+
+// ActivityStore is the read side of the per-user activity window kept by the
+// cache layer for velocity.
+type ActivityStore interface {
+	GetActivity(ctx context.Context, userID string, since time.Time) ([]domain.Activity, error)
+}
+
+type CardTestingConfig struct {
+	// Window is the span probes are looked for in. It must not exceed the
+	// longest velocity horizon, which bounds how long activity is kept.
+	Window time.Duration
+	// SmallAmount is the largest amount, in the base currency, counted as a
+	// probe.
+	SmallAmount float64
+	// MinSmall is the number of probes that alone indicate card testing.
+	MinSmall int
+	// MinRising is the length of a strictly rising run of amounts, starting
+	// from a probe and ending at the current transaction.
+	MinRising int
+	// MinMerchants is the number of distinct merchants within the window.
+	MinMerchants int
+}
+
+func DefaultCardTestingConfig() CardTestingConfig {
+	return CardTestingConfig{
+		Window:       10 * time.Minute,
+		SmallAmount:  5,
+		MinSmall:     3,
+		MinRising:    4,
+		MinMerchants: 4,
+	}
+}
+
+// CardTestingDetector flags bursts of tiny transactions, probes followed by
+// rising amounts, and spraying across merchants in a short span. Each
+// pattern found adds to the score; two or more force a review.
+type CardTestingDetector struct {
+	store ActivityStore
+	cfg   CardTestingConfig
+}
+
+func NewCardTestingDetector(store ActivityStore, cfg CardTestingConfig) *CardTestingDetector {
+	return &CardTestingDetector{store: store, cfg: cfg}
+}
+
+func (d *CardTestingDetector) Name() string {
+	return SourceCardTesting
+}
+
+func (d *CardTestingDetector) Evaluate(ctx context.Context, tx domain.Transaction, _ domain.User, facts Facts) ([]Finding, error) {
+	now := txTime(tx)
+	activity, err := d.store.GetActivity(ctx, tx.UserID, now.Add(-d.cfg.Window))
+	if err != nil {
+		return nil, err
+	}
+
+	recent := make([]domain.Activity, 0, len(activity)+1)
+	for _, a := range activity {
+		if a.TransactionID != tx.ID && !a.At.After(now) {
+			recent = append(recent, a)
+		}
+	}
+	slices.SortStableFunc(recent, func(a, b domain.Activity) int {
+		return a.At.Compare(b.At)
+	})
+	recent = append(recent, domain.Activity{TransactionID: tx.ID, Amount: tx.Amount, Merchant: tx.Merchant, At: now})
+
+	small := 0
+	merchants := make(map[string]struct{}, len(recent))
+	for _, a := range recent {
+		if a.Amount <= d.cfg.SmallAmount {
+			small++
+		}
+		if a.Merchant != "" {
+			merchants[a.Merchant] = struct{}{}
+		}
+	}
+	rising := risingRun(recent, d.cfg.SmallAmount)
+
+	facts["features.card_small_count"] = small
+	facts["features.card_rising_run"] = rising
+	facts["features.card_merchants"] = len(merchants)
+
+	var patterns []string
+	var score float64
+	if small >= d.cfg.MinSmall {
+		patterns = append(patterns, fmt.Sprintf("%d transactions of at most %.2f", small, d.cfg.SmallAmount))
+		score += 40
+	}
+	if rising >= d.cfg.MinRising {
+		patterns = append(patterns, fmt.Sprintf("%d rising amounts up to %.2f", rising, tx.Amount))
+		score += 35
+	}
+	if len(merchants) >= d.cfg.MinMerchants {
+		patterns = append(patterns, fmt.Sprintf("%d merchants", len(merchants)))
+		score += 25
+	}
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	floor := domain.DecisionAllow
+	if len(patterns) > 1 {
+		floor = domain.DecisionReview
+	}
+	return []Finding{{
+		Detector: SourceCardTesting,
+		Code:     "CARD_TESTING",
+		Score:    score,
+		Floor:    floor,
+		Message:  fmt.Sprintf("Card testing within %s: %s", d.cfg.Window, strings.Join(patterns, ", ")),
+	}}, nil
+}
+
+// risingRun is the length of the strictly rising run of amounts ending at the
+// last activity, counted only if the run starts with a probe.
+func risingRun(activity []domain.Activity, smallAmount float64) int {
+	start := len(activity) - 1
+	for start > 0 && activity[start-1].Amount < activity[start].Amount {
+		start--
+	}
+	if activity[start].Amount > smallAmount {
+		return 0
+	}
+	return len(activity) - start
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+func TestCardTestingDetector_ProbesBeforeLargePurchase(t *testing.T) {
+	now := time.Now()
+	cache := &mockCache{}
+	for i, amount := range []float64{0.5, 1, 1.5, 3} {
+		cache.activity = append(cache.activity, domain.Activity{
+			TransactionID: fmt.Sprintf("probe-%d", i),
+			Amount:        amount,
+			Merchant:      fmt.Sprintf("Web Shop %d", i),
+			At:            now.Add(time.Duration(i-4) * time.Minute),
+		})
+	}
+
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(&mockAI{}, &mockRepo{}, cache, publisher,
+		WithDetectors(NewCardTestingDetector(cache, DefaultCardTestingConfig())),
+	)
+	if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-1", UserID: "user-3", Amount: 900, Merchant: "Electronics Hub", Timestamp: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	result := publisher.PublishedAlert
+	if result.Decision == domain.DecisionAllow {
+		t.Errorf("Expected card testing to force at least REVIEW, got %s (score %d)", result.Decision, result.RiskScore)
+	}
+	if len(result.Reasons) == 0 || result.Reasons[0].Code != "CARD_TESTING" || result.Reasons[0].Score != 80 {
+		t.Errorf("Expected CARD_TESTING reason worth 80 points, got %+v", result.Reasons)
+	}
+}
+
+func TestCardTestingDetector_RegularShoppingIsIgnored(t *testing.T) {
+	now := time.Now()
+	cache := &mockCache{activity: []domain.Activity{
+		{TransactionID: "a", Amount: 42, Merchant: "Local Supermarket", At: now.Add(-8 * time.Minute)},
+		{TransactionID: "b", Amount: 3.5, Merchant: "Coffee Corner", At: now.Add(-5 * time.Minute)},
+		{TransactionID: "c", Amount: 18, Merchant: "Local Supermarket", At: now.Add(-2 * time.Minute)},
+	}}
+	d := NewCardTestingDetector(cache, DefaultCardTestingConfig())
+
+	facts := Facts{}
+	findings, err := d.Evaluate(context.Background(), domain.Transaction{ID: "tx-2", UserID: "user-3", Amount: 60, Merchant: "Pharmacy", Timestamp: now}, domain.User{}, facts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(findings) != 0 {
+		t.Errorf("Expected no card testing finding, got %+v", findings)
+	}
+	if facts["features.card_small_count"] != 1 || facts["features.card_merchants"] != 3 {
+		t.Errorf("Unexpected card testing features: %v", facts)
+	}
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type CaseRepository interface {
+	CreateCase(ctx context.Context, c *domain.ReviewCase) error
+	GetCase(ctx context.Context, id uint) (*domain.ReviewCase, error)
+	ListCases(ctx context.Context, status domain.CaseStatus, assignee string, limit int) ([]domain.ReviewCase, error)
+	UpdateCase(ctx context.Context, c *domain.ReviewCase) error
+}
+
+type CasePublisher interface {
+	Publish(ctx context.Context, c domain.ReviewCase) error
+}
+
+// RiskLabeler feeds confirmed outcomes back into the user's risk.
+type RiskLabeler interface {
+	ApplyLabel(ctx context.Context, userID string, fraud bool, actor, txID string) error
+}
+
+// CaseService runs the manual review queue: cases are opened for flagged
+// transactions, assigned to analysts and resolved as fraud or legit.
+type CaseService struct {
+	repo      CaseRepository
+	publisher CasePublisher
+	labeler   RiskLabeler
+}
+
+// NewCaseService builds the service; publisher and labeler may be nil where
+// cases are only opened.
+func NewCaseService(repo CaseRepository, publisher CasePublisher, labeler RiskLabeler) *CaseService {
+	return &CaseService{repo: repo, publisher: publisher, labeler: labeler}
+}
+
+// Open queues a flagged transaction. Opening the same transaction twice is a
+// no-op.
+func (s *CaseService) Open(ctx context.Context, c *domain.ReviewCase) error {
+	c.Status = domain.CaseOpen
+	return s.repo.CreateCase(ctx, c)
+}
+
+func (s *CaseService) Get(ctx context.Context, id uint) (*domain.ReviewCase, error) {
+	c, err := s.repo.GetCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, fmt.Errorf("%w: case %d", domain.ErrNotFound, id)
+	}
+	return c, nil
+}
+
+func (s *CaseService) List(ctx context.Context, status domain.CaseStatus, assignee string, limit int) ([]domain.ReviewCase, error) {
+	switch status {
+	case "", domain.CaseOpen, domain.CaseInvestigating, domain.CaseResolved:
+	default:
+		return nil, fmt.Errorf("%w: unknown case status %q", domain.ErrInvalidInput, status)
+	}
+	return s.repo.ListCases(ctx, status, assignee, limit)
+}
+
+// Assign hands the case to an analyst and moves it to investigating.
+func (s *CaseService) Assign(ctx context.Context, id uint, analyst, actor string) (*domain.ReviewCase, error) {
+	if strings.TrimSpace(analyst) == "" || strings.TrimSpace(actor) == "" {
+		return nil, fmt.Errorf("%w: analyst and actor are required", domain.ErrInvalidInput)
+	}
+	c, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status == domain.CaseResolved {
+		return nil, fmt.Errorf("%w: case %d is already resolved", domain.ErrInvalidInput, id)
+	}
+
+	c.AssignedTo = analyst
+	c.Status = domain.CaseInvestigating
+	if err := s.repo.UpdateCase(ctx, c); err != nil {
+		return nil, err
+	}
+	slog.Info("Case assigned", "case_id", id, "analyst", analyst, "actor", actor)
+	return c, nil
+}
+
+// Resolve records the analyst's verdict, labels the user's risk and
+// publishes the case. Resolving again with the same resolution only
+// republishes, so a failed publish can be retried.
+func (s *CaseService) Resolve(ctx context.Context, id uint, resolution domain.CaseResolution, actor, note string) (*domain.ReviewCase, error) {
+	if resolution != domain.CaseResolutionFraud && resolution != domain.CaseResolutionLegit {
+		return nil, fmt.Errorf("%w: resolution must be fraud or legit", domain.ErrInvalidInput)
+	}
+	if strings.TrimSpace(actor) == "" {
+		return nil, fmt.Errorf("%w: actor is required", domain.ErrInvalidInput)
+	}
+	c, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c.Status != domain.CaseResolved:
+		now := time.Now()
+		c.Status = domain.CaseResolved
+		c.Resolution = resolution
+		c.ResolutionNote = note
+		c.ResolvedBy = actor
+		c.ResolvedAt = &now
+		if c.AssignedTo == "" {
+			c.AssignedTo = actor
+		}
+		if err := s.repo.UpdateCase(ctx, c); err != nil {
+			return nil, err
+		}
+		slog.Info("Case resolved", "case_id", id, "tx_id", c.TransactionID, "resolution", resolution, "actor", actor)
+
+		if s.labeler != nil {
+			if err := s.labeler.ApplyLabel(ctx, c.UserID, resolution == domain.CaseResolutionFraud, actor, c.TransactionID); err != nil {
+				slog.Warn("Failed to label user risk from case", "case_id", id, "user_id", c.UserID, "err", err)
+			}
+		}
+	case c.Resolution != resolution:
+		return nil, fmt.Errorf("%w: case %d is already resolved as %s", domain.ErrInvalidInput, id, c.Resolution)
+	}
+
+	if s.publisher != nil {
+		if err := s.publisher.Publish(ctx, *c); err != nil {
+			return nil, fmt.Errorf("publish resolved case %d: %w", id, err)
+		}
+	}
+	return c, nil
+}
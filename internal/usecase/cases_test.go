@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type memoryCases struct {
+	cases []domain.ReviewCase
+}
+
+func (m *memoryCases) CreateCase(ctx context.Context, c *domain.ReviewCase) error {
+	for _, existing := range m.cases {
+		if existing.TransactionID == c.TransactionID {
+			return nil
+		}
+	}
+	c.ID = uint(len(m.cases) + 1)
+	m.cases = append(m.cases, *c)
+	return nil
+}
+
+func (m *memoryCases) GetCase(ctx context.Context, id uint) (*domain.ReviewCase, error) {
+	for _, c := range m.cases {
+		if c.ID == id {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryCases) ListCases(ctx context.Context, status domain.CaseStatus, assignee string, limit int) ([]domain.ReviewCase, error) {
+	var out []domain.ReviewCase
+	for _, c := range m.cases {
+		if (status == "" || c.Status == status) && (assignee == "" || c.AssignedTo == assignee) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryCases) UpdateCase(ctx context.Context, c *domain.ReviewCase) error {
+	m.cases[c.ID-1] = *c
+	return nil
+}
+
+type casePublisher struct {
+	published []domain.ReviewCase
+}
+
+func (p *casePublisher) Publish(ctx context.Context, c domain.ReviewCase) error {
+	p.published = append(p.published, c)
+	return nil
+}
+
+type caseLabeler struct {
+	labels map[string]bool
+}
+
+func (l *caseLabeler) ApplyLabel(ctx context.Context, userID string, fraud bool, actor, txID string) error {
+	l.labels[txID] = fraud
+	return nil
+}
+
+func TestFraudDetector_ReviewOpensCase(t *testing.T) {
+	engine, err := NewRuleEngine([]Rule{{
+		ID:         "AMT-001",
+		Name:       "Large Amount",
+		Message:    "Amount exceeds 10000",
+		Conditions: []Condition{{Field: "tx.amount", Op: OpGt, Value: 10000}},
+		Action:     RuleActionScore,
+		Score:      50,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &memoryCases{}
+	detector := NewFraudDetector(&mockAI{}, &mockRepo{}, &mockCache{}, &mockPublisher{},
+		WithRuleEngine(engine), WithCaseQueue(NewCaseService(repo, nil, nil)))
+
+	for _, tx := range []domain.Transaction{
+		{ID: "tx-review", UserID: "user-1", Amount: 15000, Merchant: "Jewelry"},
+		{ID: "tx-allow", UserID: "user-1", Amount: 50},
+	} {
+		if err := detector.Detect(context.Background(), tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(repo.cases) != 1 {
+		t.Fatalf("Expected one case for the reviewed transaction, got %+v", repo.cases)
+	}
+	c := repo.cases[0]
+	if c.TransactionID != "tx-review" || c.Status != domain.CaseOpen || c.Decision != domain.DecisionReview || c.Reason == "" {
+		t.Errorf("Unexpected case: %+v", c)
+	}
+}
+
+func TestCaseService_AssignAndResolve(t *testing.T) {
+	repo := &memoryCases{}
+	publisher := &casePublisher{}
+	labeler := &caseLabeler{labels: map[string]bool{}}
+	s := NewCaseService(repo, publisher, labeler)
+	ctx := context.Background()
+
+	if err := s.Open(ctx, &domain.ReviewCase{TransactionID: "tx-1", UserID: "user-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := s.Assign(ctx, 1, "alice", "lead")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Status != domain.CaseInvestigating || c.AssignedTo != "alice" {
+		t.Errorf("Expected the case under investigation by alice, got %+v", c)
+	}
+	if mine, _ := s.List(ctx, domain.CaseInvestigating, "alice", 10); len(mine) != 1 {
+		t.Errorf("Expected alice's queue to hold the case, got %+v", mine)
+	}
+
+	c, err = s.Resolve(ctx, 1, domain.CaseResolutionFraud, "alice", "confirmed with cardholder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Status != domain.CaseResolved || c.ResolvedAt == nil || c.ResolvedBy != "alice" {
+		t.Errorf("Expected a resolved case, got %+v", c)
+	}
+	if fraud, ok := labeler.labels["tx-1"]; !ok || !fraud {
+		t.Errorf("Expected the user to be labelled fraud, got %v", labeler.labels)
+	}
+	if len(publisher.published) != 1 || publisher.published[0].Resolution != domain.CaseResolutionFraud {
+		t.Errorf("Expected the resolution to be published, got %+v", publisher.published)
+	}
+
+	if _, err := s.Resolve(ctx, 1, domain.CaseResolutionFraud, "alice", ""); err != nil {
+		t.Errorf("Expected the same resolution to be accepted again, got %v", err)
+	}
+	if len(labeler.labels) != 1 || len(publisher.published) != 2 {
+		t.Errorf("Expected a retry to republish without relabelling, got %d publishes", len(publisher.published))
+	}
+	if _, err := s.Resolve(ctx, 1, domain.CaseResolutionLegit, "bob", ""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Expected a conflicting resolution to be rejected, got %v", err)
+	}
+	if _, err := s.Assign(ctx, 1, "bob", "lead"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Expected a resolved case not to be reassigned, got %v", err)
+	}
+	if _, err := s.Get(ctx, 42); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected not found for an unknown case, got %v", err)
+	}
+}
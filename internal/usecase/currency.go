@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type RateSource interface {
+	Base() string
+	Rate(currency string) (float64, bool)
+}
+
+// CurrencyNormalizer converts transaction amounts into the base currency so
+// profiles, rules and the AI compare like with like.
+type CurrencyNormalizer struct {
+	rates RateSource
+}
+
+func NewCurrencyNormalizer(rates RateSource) *CurrencyNormalizer {
+	return &CurrencyNormalizer{rates: rates}
+}
+
+func (n *CurrencyNormalizer) Base() string {
+	return n.rates.Base()
+}
+
+// Normalize returns tx with Amount and Currency expressed in the base
+// currency. A transaction without a currency is assumed to be in base.
+func (n *CurrencyNormalizer) Normalize(tx domain.Transaction) (domain.Transaction, error) {
+	currency := strings.ToUpper(strings.TrimSpace(tx.Currency))
+	if currency == "" {
+		currency = n.Base()
+	}
+
+	rate, ok := n.rates.Rate(currency)
+	if !ok {
+		return tx, fmt.Errorf("%w: no fx rate for currency %q", domain.ErrInvalidInput, tx.Currency)
+	}
+
+	tx.Amount = math.Round(tx.Amount*rate*100) / 100
+	tx.Currency = n.Base()
+	return tx, nil
+}
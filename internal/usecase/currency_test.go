@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type stubRates map[string]float64
+
+func (s stubRates) Base() string {
+	return "USD"
+}
+
+func (s stubRates) Rate(currency string) (float64, bool) {
+	if currency == "USD" {
+		return 1, true
+	}
+	rate, ok := s[currency]
+	return rate, ok
+}
+
+func TestCurrencyNormalizer_Normalize(t *testing.T) {
+	n := NewCurrencyNormalizer(stubRates{"EUR": 1.08, "UAH": 0.024})
+
+	tests := []struct {
+		currency string
+		amount   float64
+		want     float64
+	}{
+		{"USD", 100, 100},
+		{"", 100, 100},
+		{"eur", 100, 108},
+		{"UAH", 41500, 996},
+	}
+	for _, tt := range tests {
+		got, err := n.Normalize(domain.Transaction{Amount: tt.amount, Currency: tt.currency})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.currency, err)
+		}
+		if got.Amount != tt.want || got.Currency != "USD" {
+			t.Errorf("%s %.2f: expected %.2f USD, got %.2f %s", tt.currency, tt.amount, tt.want, got.Amount, got.Currency)
+		}
+	}
+
+	if _, err := n.Normalize(domain.Transaction{Amount: 1, Currency: "XYZ"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput for unknown currency, got %v", err)
+	}
+}
+
+func TestFraudDetector_StoresOriginalAndNormalizedAmounts(t *testing.T) {
+	repo := &mockRepo{}
+	ai := &mockAI{}
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(ai, repo, &mockCache{}, publisher,
+		WithCurrencyNormalizer(NewCurrencyNormalizer(stubRates{"UAH": 0.024})),
+	)
+
+	tx := domain.Transaction{ID: "tx-1", UserID: "user-3", Amount: 41500, Currency: "UAH", Merchant: "Local Supermarket"}
+	if err := detector.Detect(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+
+	event := repo.events[0]
+	if event.Amount != 996 || event.Currency != "USD" {
+		t.Errorf("Expected normalized 996 USD, got %.2f %s", event.Amount, event.Currency)
+	}
+	if event.OriginalAmount != 41500 || event.OriginalCurrency != "UAH" {
+		t.Errorf("Expected original 41500 UAH, got %.2f %s", event.OriginalAmount, event.OriginalCurrency)
+	}
+	if publisher.PublishedAlert.OriginalCurrency != "UAH" {
+		t.Errorf("Expected alert to carry original currency, got %q", publisher.PublishedAlert.OriginalCurrency)
+	}
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+// DegradedRule sets the outcome for transactions matching all its
+// conditions while the AI is unavailable. Besides the usual facts,
+// conditions may use the results of the AI-less evaluation: rules.score,
+// rules.hits, decision.score and decision.floor, the lowest decision forced
+// by rule and detector floors.
+type DegradedRule struct {
+	ID         string      `json:"id" yaml:"id"`
+	Message    string      `json:"message" yaml:"message"`
+	Conditions []Condition `json:"conditions" yaml:"conditions"`
+	Decision   string      `json:"decision" yaml:"decision"`
+}
+
+type DegradedPolicyConfig struct {
+	Default string         `json:"default" yaml:"default"`
+	Rules   []DegradedRule `json:"rules" yaml:"rules"`
+}
+
+// DefaultDegradedPolicyConfig fails closed for large amounts of risky users,
+// sends other large amounts and high-risk merchants to review and fails open
+// for everything else.
+func DefaultDegradedPolicyConfig() DegradedPolicyConfig {
+	return DegradedPolicyConfig{
+		Default: string(domain.DecisionAllow),
+		Rules: []DegradedRule{
+			{
+				ID:      "DEG-001",
+				Message: "Large amount from a high-risk user",
+				Conditions: []Condition{
+					{Field: "tx.amount", Op: OpGt, Value: 5000},
+					{Field: "user.risk_score", Op: OpGt, Value: 70},
+				},
+				Decision: string(domain.DecisionBlock),
+			},
+			{
+				ID:         "DEG-002",
+				Message:    "Large amount",
+				Conditions: []Condition{{Field: "tx.amount", Op: OpGt, Value: 5000}},
+				Decision:   string(domain.DecisionReview),
+			},
+			{
+				ID:         "DEG-003",
+				Message:    "High-risk merchant",
+				Conditions: []Condition{{Field: "merchant.risk_tier", Op: OpEq, Value: string(domain.MerchantRiskHigh)}},
+				Decision:   string(domain.DecisionReview),
+			},
+		},
+	}
+}
+
+func LoadDegradedPolicy(path string) (DegradedPolicyConfig, error) {
+	var cfg DegradedPolicyConfig
+	if err := decodeFile(path, &cfg); err != nil {
+		return cfg, fmt.Errorf("degraded policy file: %w", err)
+	}
+	return cfg, nil
+}
+
+// DegradedPolicy picks the fallback outcome of a transaction when the AI
+// fails: the decision of the first matching rule, else the default. ALLOW
+// fails open and BLOCK fails closed. The outcome is a floor, so it never
+// relaxes what rules and detectors decided without the AI.
+type DegradedPolicy struct {
+	def   domain.Decision
+	rules []degradedRule
+}
+
+type degradedRule struct {
+	DegradedRule
+	decision domain.Decision
+}
+
+func NewDegradedPolicy(cfg DegradedPolicyConfig) (*DegradedPolicy, error) {
+	def, ok := parseDecision(cfg.Default)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown default decision %q", domain.ErrInvalidInput, cfg.Default)
+	}
+	p := &DegradedPolicy{def: def}
+	for _, r := range cfg.Rules {
+		if r.ID == "" {
+			return nil, fmt.Errorf("%w: degraded rule without id", domain.ErrInvalidInput)
+		}
+		decision, ok := parseDecision(r.Decision)
+		if !ok {
+			return nil, fmt.Errorf("%w: degraded rule %s: unknown decision %q", domain.ErrInvalidInput, r.ID, r.Decision)
+		}
+		if err := checkConditions(r.Conditions); err != nil {
+			return nil, fmt.Errorf("%w: degraded rule %s: %v", domain.ErrInvalidInput, r.ID, err)
+		}
+		p.rules = append(p.rules, degradedRule{DegradedRule: r, decision: decision})
+	}
+	return p, nil
+}
+
+func parseDecision(s string) (domain.Decision, bool) {
+	return domain.ParseDecision(strings.ToUpper(strings.TrimSpace(s)))
+}
+
+// apply raises the AI-less decision to the policy's fallback and returns
+// the reason explaining it.
+func (p *DegradedPolicy) apply(facts Facts, verdict ruleVerdict, score int, decision domain.Decision) (domain.Decision, domain.Reason) {
+	facts = maps.Clone(facts)
+	facts["rules.score"] = verdict.score
+	facts["rules.hits"] = len(verdict.hits) + len(verdict.findings)
+	facts["decision.score"] = score
+	facts["decision.floor"] = string(verdict.floor())
+
+	fallback, code, message := p.def, "DEGRADED_DEFAULT", "No degraded rule matched"
+	for _, r := range p.rules {
+		if matchAll(r.Conditions, facts) {
+			fallback, code, message = r.decision, r.ID, r.Message
+			break
+		}
+	}
+	return domain.MaxDecision(decision, fallback), domain.Reason{
+		Code:    code,
+		Source:  domain.ReasonSourceDegraded,
+		Message: fmt.Sprintf("%s, fallback %s while the AI is unavailable", message, fallback),
+	}
+}
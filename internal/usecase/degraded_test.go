@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+func TestFraudDetector_DegradedPolicyFailsClosedForRiskyLargeAmounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		risk     int
+		amount   float64
+		decision domain.Decision
+		code     string
+	}{
+		{"risky user, large amount", 80, 6000, domain.DecisionBlock, "DEG-001"},
+		{"regular user, large amount", 20, 6000, domain.DecisionReview, "DEG-002"},
+		{"small amount", 80, 50, domain.DecisionAllow, "DEGRADED_DEFAULT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepo{user: &domain.User{ID: "user-1", RiskScore: tt.risk}}
+			publisher := &mockPublisher{}
+			detector := NewFraudDetector(&mockAI{fail: true}, repo, &mockCache{}, publisher)
+
+			if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-1", UserID: "user-1", Amount: tt.amount}); err != nil {
+				t.Fatal(err)
+			}
+
+			alert := publisher.PublishedAlert
+			if alert.Decision != tt.decision || !alert.Degraded {
+				t.Errorf("Expected a degraded %s, got %s (degraded=%v)", tt.decision, alert.Decision, alert.Degraded)
+			}
+			last := alert.Reasons[len(alert.Reasons)-1]
+			if last.Code != tt.code || last.Source != domain.ReasonSourceDegraded {
+				t.Errorf("Expected the fallback to be explained by %s, got %+v", tt.code, last)
+			}
+			if len(repo.events) != 1 || !repo.events[0].Degraded {
+				t.Errorf("Expected the event to be marked degraded")
+			}
+		})
+	}
+}
+
+func TestFraudDetector_DegradedPolicyNeverRelaxes(t *testing.T) {
+	engine, _ := NewRuleEngine([]Rule{{
+		ID:         "MER-001",
+		Name:       "Blocked Merchant",
+		Conditions: []Condition{{Field: "tx.merchant", Op: OpEq, Value: "Casino"}},
+		Action:     RuleActionBlock,
+	}})
+	policy, err := NewDegradedPolicy(DegradedPolicyConfig{Default: "allow"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(&mockAI{fail: true}, &mockRepo{}, &mockCache{}, publisher,
+		WithRuleEngine(engine), WithDegradedPolicy(policy))
+
+	if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-1", UserID: "user-1", Amount: 10, Merchant: "Casino"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := publisher.PublishedAlert; got.Decision != domain.DecisionBlock || !got.Degraded {
+		t.Errorf("Expected the rule block to stand in degraded mode, got %s (degraded=%v)", got.Decision, got.Degraded)
+	}
+}
+
+func TestFraudDetector_HealthyAIIsNotDegraded(t *testing.T) {
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(&mockAI{}, &mockRepo{user: &domain.User{ID: "user-1", RiskScore: 80}}, &mockCache{}, publisher)
+
+	if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-1", UserID: "user-1", Amount: 6000}); err != nil {
+		t.Fatal(err)
+	}
+	if got := publisher.PublishedAlert; got.Degraded || got.Decision == domain.DecisionBlock {
+		t.Errorf("Expected the degraded policy to stay out of healthy decisions, got %s (degraded=%v)", got.Decision, got.Degraded)
+	}
+}
+
+func TestNewDegradedPolicy_Validates(t *testing.T) {
+	bad := []DegradedPolicyConfig{
+		{Default: "deny"},
+		{Default: "ALLOW", Rules: []DegradedRule{{ID: "X", Decision: "BLOCK"}}},
+		{Default: "ALLOW", Rules: []DegradedRule{{ID: "X", Decision: "maybe", Conditions: []Condition{{Field: "tx.amount", Op: OpGt, Value: 1}}}}},
+		{Default: "ALLOW", Rules: []DegradedRule{{Decision: "BLOCK", Conditions: []Condition{{Field: "tx.amount", Op: OpGt, Value: 1}}}}},
+	}
+	for _, cfg := range bad {
+		if _, err := NewDegradedPolicy(cfg); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("Expected %+v to be rejected, got %v", cfg, err)
+		}
+	}
+}
+
+func TestDegradedPolicy_FloorFactIgnoresScore(t *testing.T) {
+	policy, err := NewDegradedPolicy(DegradedPolicyConfig{
+		Default: "ALLOW",
+		Rules: []DegradedRule{{
+			ID:         "X",
+			Conditions: []Condition{{Field: "decision.floor", Op: OpEq, Value: "REVIEW"}},
+			Decision:   "BLOCK",
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A score-driven REVIEW is not a floor.
+	if got, _ := policy.apply(Facts{}, ruleVerdict{}, 60, domain.DecisionReview); got != domain.DecisionReview {
+		t.Errorf("Expected a score-driven review not to match the floor, got %s", got)
+	}
+	if got, _ := policy.apply(Facts{}, ruleVerdict{review: true}, 60, domain.DecisionReview); got != domain.DecisionBlock {
+		t.Errorf("Expected a forced review to match the floor, got %s", got)
+	}
+}
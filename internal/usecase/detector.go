@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+// Finding is a detector's contribution to a decision. Score is a 0-100
+// signal weighted under the detector's name; Floor is the minimum decision
+// the finding enforces regardless of the total score.
+type Finding struct {
+	Detector string
+	Code     string
+	Score    float64
+	Floor    domain.Decision
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("[%s] %s", f.Code, f.Message)
+}
+
+// Detector evaluates a transaction before rules run. Detectors may publish
+// features into facts so rules can reference them.
+type Detector interface {
+	Name() string
+	Evaluate(ctx context.Context, tx domain.Transaction, user domain.User, facts Facts) ([]Finding, error)
+}
+
+// Recorder is implemented by detectors that keep per-user state and need to
+// observe the final decision once it has been made.
+type Recorder interface {
+	Record(ctx context.Context, tx domain.Transaction, decision domain.Decision) error
+}
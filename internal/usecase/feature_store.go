@@ -0,0 +1,280 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+const amountProfileBootstrapLimit = 1000
+
+func DefaultFeatureDefinitions() []domain.FeatureDefinition {
+	return []domain.FeatureDefinition{
+		{Name: "transaction", Version: 1, Prefixes: []string{"tx.amount", "tx.original_amount"}, Description: "Amount in the base and the original currency"},
+		{Name: "user", Version: 1, Prefixes: []string{"user.risk_score", "user.max_tx", "user.avg_tx"}, Description: "Decayed risk score and amount profile"},
+		{Name: "velocity", Version: 1, Prefixes: []string{"features.velocity"}, Description: "Count, amount and locations per velocity horizon"},
+		{Name: "amount", Version: 1, Prefixes: []string{"features.amount_"}, Description: "Amount z-score and percentiles against the user's profile"},
+		{Name: "travel", Version: 1, Prefixes: []string{"features.travel_"}, Description: "Distance and speed since the last known position"},
+		{Name: "ip_intel", Version: 1, Prefixes: []string{"features.ip_"}, Description: "IP reputation and per-IP usage"},
+		{Name: "ring", Version: 1, Prefixes: []string{"features.ring_"}, Description: "Linked accounts and shared entities"},
+		{Name: "novelty", Version: 1, Prefixes: []string{"features.is_new_", "features.days_since_first_seen"}, Description: "First use of merchant, location, country and IP"},
+		{Name: "trust", Version: 1, Prefixes: []string{"trust."}, Description: "Trusted merchant, location and IP"},
+		{Name: "card_testing", Version: 1, Prefixes: []string{"features.card_"}, Description: "Probes, rising runs and merchant spread"},
+		{Name: "structuring", Version: 1, Prefixes: []string{"features.structuring_"}, Description: "Near-threshold amounts of the user and linked accounts"},
+	}
+}
+
+// FeatureRegistry selects the registered features out of the facts of a
+// decision.
+type FeatureRegistry struct {
+	defs    []domain.FeatureDefinition
+	version string
+}
+
+func NewFeatureRegistry(defs []domain.FeatureDefinition) (*FeatureRegistry, error) {
+	seen := make(map[string]struct{}, len(defs))
+	ids := make([]string, 0, len(defs))
+	for _, d := range defs {
+		if d.Name == "" || d.Version <= 0 || len(d.Prefixes) == 0 {
+			return nil, fmt.Errorf("%w: feature definition %q needs a name, a positive version and prefixes", domain.ErrInvalidInput, d.Name)
+		}
+		if _, ok := seen[d.Name]; ok {
+			return nil, fmt.Errorf("%w: duplicate feature definition %q", domain.ErrInvalidInput, d.Name)
+		}
+		seen[d.Name] = struct{}{}
+		ids = append(ids, d.Name+"."+strconv.Itoa(d.Version))
+	}
+	slices.Sort(ids)
+
+	h := fnv.New32a()
+	h.Write([]byte(strings.Join(ids, ",")))
+	return &FeatureRegistry{defs: defs, version: "fs-" + strconv.FormatUint(uint64(h.Sum32()), 16)}, nil
+}
+
+func (r *FeatureRegistry) Definitions() []domain.FeatureDefinition {
+	return r.defs
+}
+
+// Version identifies the set of definitions, names and versions included.
+func (r *FeatureRegistry) Version() string {
+	return r.version
+}
+
+// Vector keeps the numeric and boolean facts under registered prefixes;
+// booleans become 0 or 1, anything else is left out.
+func (r *FeatureRegistry) Vector(tx domain.Transaction, facts Facts) domain.FeatureVector {
+	values := make(map[string]float64)
+	for name, v := range facts {
+		if !r.registered(name) {
+			continue
+		}
+		switch x := v.(type) {
+		case bool:
+			values[name] = 0
+			if x {
+				values[name] = 1
+			}
+		case string:
+		default:
+			if f, ok := toFloat(x); ok {
+				values[name] = f
+			}
+		}
+	}
+	return domain.FeatureVector{TransactionID: tx.ID, Version: r.version, Values: values, At: txTime(tx)}
+}
+
+func (r *FeatureRegistry) registered(name string) bool {
+	for _, d := range r.defs {
+		for _, p := range d.Prefixes {
+			if strings.HasPrefix(name, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// OnlineFeatureStore keeps recent feature vectors per user for low-latency
+// point-in-time reads.
+type OnlineFeatureStore interface {
+	PutFeatureVector(ctx context.Context, userID string, v domain.FeatureVector, retention time.Duration) error
+	GetFeatureVector(ctx context.Context, userID string, at time.Time) (*domain.FeatureVector, error)
+}
+
+type FeatureSnapshotRepository interface {
+	SaveFeatureSnapshot(ctx context.Context, s *domain.FeatureSnapshot) error
+	GetFeatureSnapshot(ctx context.Context, userID string, at time.Time) (*domain.FeatureSnapshot, error)
+}
+
+// AmountHistory bootstraps amount profiles for users without one.
+type AmountHistory interface {
+	GetUserAmounts(ctx context.Context, userID string, limit int) ([]float64, error)
+}
+
+// FeatureBackends are where the feature store keeps its data. State holds
+// the activity and amount profiles the velocity and amount features are
+// computed from and is required by Load and Record; the others may be nil.
+type FeatureBackends struct {
+	State     CacheRepository
+	Amounts   AmountHistory
+	Online    OnlineFeatureStore
+	Snapshots FeatureSnapshotRepository
+}
+
+// FeatureStore serves the user features a decision is made on, logs the
+// registered facts of every decision as a versioned vector to the online
+// store and the snapshot log, and answers "what did this user's features
+// look like at time t".
+type FeatureStore struct {
+	registry  *FeatureRegistry
+	backends  FeatureBackends
+	horizons  []VelocityHorizon
+	retention time.Duration
+}
+
+// NewFeatureStore builds the store; retention bounds how long vectors stay
+// in the online store.
+func NewFeatureStore(registry *FeatureRegistry, backends FeatureBackends, horizons []VelocityHorizon, retention time.Duration) *FeatureStore {
+	return &FeatureStore{registry: registry, backends: backends, horizons: horizons, retention: retention}
+}
+
+func (s *FeatureStore) Definitions() []domain.FeatureDefinition {
+	return s.registry.Definitions()
+}
+
+func (s *FeatureStore) Version() string {
+	return s.registry.Version()
+}
+
+// OnlineFeatures are the user's velocity windows and amount profile as of
+// the transaction, before it is recorded.
+type OnlineFeatures struct {
+	Profile  *domain.AmountProfile
+	Velocity map[string]VelocityWindow
+	horizons []VelocityHorizon
+}
+
+// Facts publishes the velocity features and returns the count in the
+// shortest horizon.
+func (f OnlineFeatures) Facts(facts Facts) int {
+	return velocityFeatures(f.Velocity, f.horizons, facts)
+}
+
+// Load reads the user's online features. Read failures are logged and
+// leave the affected features empty.
+func (s *FeatureStore) Load(ctx context.Context, tx domain.Transaction) OnlineFeatures {
+	now := txTime(tx)
+	activity, err := s.backends.State.GetActivity(ctx, tx.UserID, now.Add(-s.horizons[0].Window))
+	if err != nil {
+		slog.Warn("Failed to load velocity window", "user_id", tx.UserID, "err", err)
+	}
+	stats, err := s.backends.State.GetActivityStats(ctx, tx.UserID, now, velocityStatWindows(s.horizons))
+	if err != nil {
+		slog.Warn("Failed to load velocity stats", "user_id", tx.UserID, "err", err)
+	}
+	return OnlineFeatures{
+		Profile:  s.loadAmountProfile(ctx, tx.UserID),
+		Velocity: velocityWindows(activity, stats, now, s.horizons),
+		horizons: s.horizons,
+	}
+}
+
+func (s *FeatureStore) loadAmountProfile(ctx context.Context, userID string) *domain.AmountProfile {
+	profile, err := s.backends.State.GetAmountProfile(ctx, userID)
+	if err == nil && profile != nil {
+		return profile
+	}
+	if s.backends.Amounts == nil {
+		return domain.NewAmountProfile()
+	}
+
+	amounts, err := s.backends.Amounts.GetUserAmounts(ctx, userID, amountProfileBootstrapLimit)
+	if err != nil {
+		slog.Warn("Failed to bootstrap amount profile", "user_id", userID, "err", err)
+	}
+	return domain.NewAmountProfile(amounts...)
+}
+
+// Record adds the decided transaction to the user's online state. Blocked
+// amounts are kept out of the amount profile.
+func (s *FeatureStore) Record(ctx context.Context, tx domain.Transaction, decision domain.Decision, features OnlineFeatures) {
+	if err := s.backends.State.RecordActivity(ctx, tx.UserID, domain.Activity{
+		TransactionID: tx.ID,
+		Amount:        tx.Amount,
+		Merchant:      tx.Merchant,
+		Location:      tx.Location,
+		IP:            tx.IP,
+		At:            txTime(tx),
+	}, velocityRetention(s.horizons)); err != nil {
+		slog.Warn("Failed to record velocity", "user_id", tx.UserID, "err", err)
+	}
+	if decision == domain.DecisionBlock {
+		return
+	}
+	features.Profile.Add(tx.Amount)
+	if err := s.backends.State.SetAmountProfile(ctx, tx.UserID, features.Profile); err != nil {
+		slog.Warn("Failed to update amount profile", "user_id", tx.UserID, "err", err)
+	}
+}
+
+// Log writes the feature vector of a decision to the snapshot log and the
+// online store, whichever are configured.
+func (s *FeatureStore) Log(ctx context.Context, tx domain.Transaction, facts Facts, decision domain.Decision, score int) error {
+	v := s.registry.Vector(tx, facts)
+	if s.backends.Snapshots != nil {
+		if err := s.backends.Snapshots.SaveFeatureSnapshot(ctx, &domain.FeatureSnapshot{
+			TransactionID: tx.ID,
+			UserID:        tx.UserID,
+			Version:       v.Version,
+			Values:        v.Values,
+			Decision:      decision,
+			RiskScore:     score,
+			At:            v.At,
+		}); err != nil {
+			return fmt.Errorf("save feature snapshot: %w", err)
+		}
+	}
+	if s.backends.Online != nil {
+		if err := s.backends.Online.PutFeatureVector(ctx, tx.UserID, v, s.retention); err != nil {
+			return fmt.Errorf("put online features: %w", err)
+		}
+	}
+	return nil
+}
+
+// At returns the user's latest feature vector logged at or before at,
+// preferring the online store and falling back to the snapshot log.
+func (s *FeatureStore) At(ctx context.Context, userID string, at time.Time) (*domain.FeatureVector, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, fmt.Errorf("%w: user id is required", domain.ErrInvalidInput)
+	}
+	if s.backends.Online != nil {
+		v, err := s.backends.Online.GetFeatureVector(ctx, userID, at)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			return v, nil
+		}
+	}
+	var snap *domain.FeatureSnapshot
+	if s.backends.Snapshots != nil {
+		var err error
+		if snap, err = s.backends.Snapshots.GetFeatureSnapshot(ctx, userID, at); err != nil {
+			return nil, err
+		}
+	}
+	if snap == nil {
+		return nil, fmt.Errorf("%w: no features for user %s at %s", domain.ErrNotFound, userID, at.Format(time.RFC3339))
+	}
+	v := snap.Vector()
+	return &v, nil
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type memorySnapshots struct {
+	snapshots []domain.FeatureSnapshot
+}
+
+func (m *memorySnapshots) SaveFeatureSnapshot(ctx context.Context, s *domain.FeatureSnapshot) error {
+	m.snapshots = append(m.snapshots, *s)
+	return nil
+}
+
+func (m *memorySnapshots) GetFeatureSnapshot(ctx context.Context, userID string, at time.Time) (*domain.FeatureSnapshot, error) {
+	var found *domain.FeatureSnapshot
+	for i, s := range m.snapshots {
+		if s.UserID == userID && !s.At.After(at) {
+			found = &m.snapshots[i]
+		}
+	}
+	return found, nil
+}
+
+type memoryOnlineFeatures struct {
+	vectors []domain.FeatureVector
+}
+
+func (m *memoryOnlineFeatures) PutFeatureVector(ctx context.Context, userID string, v domain.FeatureVector, retention time.Duration) error {
+	m.vectors = append(m.vectors, v)
+	return nil
+}
+
+func (m *memoryOnlineFeatures) GetFeatureVector(ctx context.Context, userID string, at time.Time) (*domain.FeatureVector, error) {
+	var found *domain.FeatureVector
+	for i, v := range m.vectors {
+		if !v.At.After(at) {
+			found = &m.vectors[i]
+		}
+	}
+	return found, nil
+}
+
+func TestFeatureStore_LogsVersionedSnapshotPerDecision(t *testing.T) {
+	registry, err := NewFeatureRegistry(DefaultFeatureDefinitions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	state := &mockCache{activity: []domain.Activity{{TransactionID: "old", Amount: 10, At: now.Add(-30 * time.Second)}}}
+	snapshots, online := &memorySnapshots{}, &memoryOnlineFeatures{}
+	store := NewFeatureStore(registry, FeatureBackends{State: state, Online: online, Snapshots: snapshots}, DefaultVelocityHorizons(), time.Hour)
+
+	detector := NewFraudDetector(&mockAI{}, &mockRepo{}, &mockCache{}, &mockPublisher{}, WithFeatureStore(store))
+	if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-1", UserID: "user-1", Amount: 250, Merchant: "Coffee Corner", Timestamp: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(snapshots.snapshots) != 1 || len(online.vectors) != 1 {
+		t.Fatalf("Expected one snapshot and one online vector, got %d and %d", len(snapshots.snapshots), len(online.vectors))
+	}
+	if len(state.activity) != 2 {
+		t.Errorf("Expected the transaction to be recorded in the feature store's state, got %+v", state.activity)
+	}
+	s := snapshots.snapshots[0]
+	if s.Version != registry.Version() || s.Decision != domain.DecisionAllow || !s.At.Equal(now) {
+		t.Errorf("Unexpected snapshot header: %+v", s)
+	}
+	if s.Values["tx.amount"] != 250 || s.Values["features.velocity_1m_count"] != 1 {
+		t.Errorf("Expected amount and velocity features read from the feature store, got %v", s.Values)
+	}
+	if _, ok := s.Values["tx.merchant"]; ok {
+		t.Errorf("Expected string facts to be left out, got %v", s.Values)
+	}
+}
+
+func TestFeatureStore_PointInTimeLookup(t *testing.T) {
+	registry, _ := NewFeatureRegistry(DefaultFeatureDefinitions())
+	snapshots := &memorySnapshots{}
+	store := NewFeatureStore(registry, FeatureBackends{Snapshots: snapshots}, nil, 0)
+
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i, amount := range []float64{100, 200, 300} {
+		tx := domain.Transaction{ID: string(rune('a' + i)), UserID: "user-1", Amount: amount, Timestamp: start.Add(time.Duration(i) * time.Hour)}
+		if err := store.Log(context.Background(), tx, Facts{"tx.amount": amount, "trust.all": i == 2}, domain.DecisionAllow, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v, err := store.At(context.Background(), "user-1", start.Add(90*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.TransactionID != "b" || v.Values["tx.amount"] != 200 || v.Values["trust.all"] != 0 {
+		t.Errorf("Expected the vector logged at 13:00, got %+v", v)
+	}
+	if _, err := store.At(context.Background(), "user-1", start.Add(-time.Minute)); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected not found before the first decision, got %v", err)
+	}
+}
+
+func TestFeatureStore_AtPrefersOnlineStore(t *testing.T) {
+	registry, _ := NewFeatureRegistry(DefaultFeatureDefinitions())
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	snapshots := &memorySnapshots{snapshots: []domain.FeatureSnapshot{{TransactionID: "snap", UserID: "user-1", At: at}}}
+	online := &memoryOnlineFeatures{}
+	store := NewFeatureStore(registry, FeatureBackends{Online: online, Snapshots: snapshots}, nil, time.Hour)
+
+	if v, err := store.At(context.Background(), "user-1", at); err != nil || v.TransactionID != "snap" {
+		t.Fatalf("Expected the snapshot while the online store is empty, got %+v, %v", v, err)
+	}
+	online.vectors = append(online.vectors, domain.FeatureVector{TransactionID: "online", At: at})
+	if v, err := store.At(context.Background(), "user-1", at); err != nil || v.TransactionID != "online" {
+		t.Errorf("Expected the online vector, got %+v, %v", v, err)
+	}
+}
+
+func TestFeatureRegistry_VersionTracksDefinitions(t *testing.T) {
+	defs := DefaultFeatureDefinitions()
+	base, _ := NewFeatureRegistry(defs)
+
+	defs[0].Version++
+	bumped, _ := NewFeatureRegistry(defs)
+	if base.Version() == bumped.Version() {
+		t.Errorf("Expected a version bump to change the feature set version %s", base.Version())
+	}
+
+	if _, err := NewFeatureRegistry(append(defs, defs[0])); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Expected duplicate definitions to be rejected, got %v", err)
+	}
+}
@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/tokyosplif/fraud-core/internal/domain"
 )
@@ -15,16 +17,17 @@ type Repository interface {
 	GetUserByID(ctx context.Context, id string) (*domain.User, error)
 	CreateUser(ctx context.Context, user *domain.User) error
 	SaveFraudEvent(ctx context.Context, event *domain.FraudEvent) error
-	GetUserStats(ctx context.Context, userID string) (float64, float64, error)
+	GetUserAmounts(ctx context.Context, userID string, limit int) ([]float64, error)
 }
 
 type CacheRepository interface {
-	GetVelocity(ctx context.Context, userID string) (int, error)
-	IncrementVelocity(ctx context.Context, userID, location string) error
-	GetUserStats(ctx context.Context, userID string) (float64, float64, error)
-	SetUserStats(ctx context.Context, userID string, maxAmount, avgAmount float64) error
-	GetRiskCache(ctx context.Context, userID, merchant string) (*domain.FraudAlert, error)
-	SetRiskCache(ctx context.Context, userID, merchant string, alert domain.FraudAlert) error
+	GetActivity(ctx context.Context, userID string, since time.Time) ([]domain.Activity, error)
+	// GetActivityStats aggregates the activity in (now-window, now] for each
+	// window without loading it.
+	GetActivityStats(ctx context.Context, userID string, now time.Time, windows []time.Duration) ([]domain.ActivityStats, error)
+	RecordActivity(ctx context.Context, userID string, activity domain.Activity, retention time.Duration) error
+	GetAmountProfile(ctx context.Context, userID string) (*domain.AmountProfile, error)
+	SetAmountProfile(ctx context.Context, userID string, profile *domain.AmountProfile) error
 }
 
 type FraudPublisher interface {
@@ -32,102 +35,568 @@ type FraudPublisher interface {
 }
 
 type FraudDetector struct {
-	aiClient      AIClient
-	repo          Repository
-	cache         CacheRepository
-	publisher     FraudPublisher
-	velocityLimit int
+	aiClient  AIClient
+	repo      Repository
+	publisher FraudPublisher
+	rules     *RuleEngine
+	scorer    *Scorer
+	detectors []Detector
+	anomaly   AmountAnomalyConfig
+	bans      *BanService
+	risk      *RiskUpdater
+	fx        *CurrencyNormalizer
+	shadows   []Shadow
+	shadowLog ShadowRepository
+	trustSkip bool
+	history   *EntityHistory
+	features  *FeatureStore
+	cases     *CaseService
+	degraded  *DegradedPolicy
+	riskCache *RiskCache
 }
 
-func NewFraudDetector(ai AIClient, r Repository, c CacheRepository, p FraudPublisher) *FraudDetector {
-	return &FraudDetector{
-		aiClient:      ai,
-		repo:          r,
-		cache:         c,
-		publisher:     p,
-		velocityLimit: 10,
+type DetectorOption func(*FraudDetector)
+
+func WithScorer(s *Scorer) DetectorOption {
+	return func(d *FraudDetector) {
+		d.scorer = s
+	}
+}
+
+func WithDetectors(detectors ...Detector) DetectorOption {
+	return func(d *FraudDetector) {
+		d.detectors = append(d.detectors, detectors...)
+	}
+}
+
+func WithAmountAnomaly(cfg AmountAnomalyConfig) DetectorOption {
+	return func(d *FraudDetector) {
+		d.anomaly = cfg
+	}
+}
+
+func WithBanService(s *BanService) DetectorOption {
+	return func(d *FraudDetector) {
+		d.bans = s
 	}
 }
 
+func WithRiskUpdater(u *RiskUpdater) DetectorOption {
+	return func(d *FraudDetector) {
+		d.risk = u
+	}
+}
+
+func WithRuleEngine(e *RuleEngine) DetectorOption {
+	return func(d *FraudDetector) {
+		d.rules = e
+	}
+}
+
+func WithShadows(repo ShadowRepository, shadows ...Shadow) DetectorOption {
+	return func(d *FraudDetector) {
+		d.shadowLog = repo
+		d.shadows = append(d.shadows, shadows...)
+	}
+}
+
+// WithTrustedAISkip skips the AI call when every entity of the transaction
+// is on the user's trusted list (the "trust.all" fact).
+func WithTrustedAISkip() DetectorOption {
+	return func(d *FraudDetector) {
+		d.trustSkip = true
+	}
+}
+
+func WithEntityHistory(h *EntityHistory) DetectorOption {
+	return func(d *FraudDetector) {
+		d.history = h
+	}
+}
+
+// WithFeatureStore replaces the default feature store, which reads velocity
+// and amount profiles from the detector's cache repository and logs nothing.
+func WithFeatureStore(s *FeatureStore) DetectorOption {
+	return func(d *FraudDetector) {
+		d.features = s
+	}
+}
+
+// WithCaseQueue opens a review case for every REVIEW decision.
+func WithCaseQueue(s *CaseService) DetectorOption {
+	return func(d *FraudDetector) {
+		d.cases = s
+	}
+}
+
+// WithDegradedPolicy sets how transactions are decided while the AI fails.
+func WithDegradedPolicy(p *DegradedPolicy) DetectorOption {
+	return func(d *FraudDetector) {
+		d.degraded = p
+	}
+}
+
+// WithRiskCache reuses AI verdicts given in the same context.
+func WithRiskCache(c *RiskCache) DetectorOption {
+	return func(d *FraudDetector) {
+		d.riskCache = c
+	}
+}
+
+func WithCurrencyNormalizer(n *CurrencyNormalizer) DetectorOption {
+	return func(d *FraudDetector) {
+		d.fx = n
+	}
+}
+
+func NewFraudDetector(ai AIClient, r Repository, c CacheRepository, p FraudPublisher, opts ...DetectorOption) *FraudDetector {
+	d := &FraudDetector{
+		aiClient:  ai,
+		repo:      r,
+		publisher: p,
+		anomaly:   DefaultAmountAnomalyConfig(),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.rules == nil {
+		d.rules, _ = NewRuleEngine(DefaultRules())
+	}
+	if d.scorer == nil {
+		d.scorer, _ = NewScorer(DefaultScoringConfig())
+	}
+	if d.degraded == nil {
+		d.degraded, _ = NewDegradedPolicy(DefaultDegradedPolicyConfig())
+	}
+	if d.features == nil {
+		registry, _ := NewFeatureRegistry(DefaultFeatureDefinitions())
+		d.features = NewFeatureStore(registry, FeatureBackends{State: c, Amounts: r}, DefaultVelocityHorizons(), 0)
+	}
+	return d
+}
+
 func (d *FraudDetector) Detect(ctx context.Context, tx domain.Transaction) error {
+	original := tx
+	tx = d.normalize(tx)
+
 	user, _ := d.repo.GetUserByID(ctx, tx.UserID)
 	if user == nil {
-		user = &domain.User{ID: tx.UserID, RiskScore: 15}
+		user = &domain.User{ID: tx.UserID, RiskScore: 15, RiskBaseline: 15}
 		_ = d.repo.CreateUser(ctx, user)
 	}
+	if user.IsBanned {
+		return d.detectBanned(ctx, tx, original, user)
+	}
+
+	stored := *user
+	if d.risk != nil {
+		user.RiskScore = d.risk.Effective(stored)
+	}
 
-	maxTx, avgTx, _ := d.cache.GetUserStats(ctx, tx.UserID)
-	if maxTx == 0 && avgTx == 0 {
-		maxTx, avgTx, _ = d.repo.GetUserStats(ctx, tx.UserID)
-		_ = d.cache.SetUserStats(ctx, tx.UserID, maxTx, avgTx)
+	online := d.features.Load(ctx, tx)
+	profile := online.Profile
+	user.MaxTx = profile.Max
+	user.AvgTx = profile.Mean
+	user.Novelty = d.loadNovelty(ctx, tx)
+
+	facts := buildFacts(tx, original, *user)
+	vel := online.Facts(facts)
+	amountFeatures(profile, tx.Amount, facts)
+	if user.Novelty != nil {
+		noveltyFeatures(*user.Novelty, facts)
 	}
-	user.MaxTx = maxTx
-	user.AvgTx = avgTx
 
-	isVelocityFraud := false
-	vel, _ := d.cache.GetVelocity(ctx, tx.UserID)
-	if vel > d.velocityLimit {
-		isVelocityFraud = true
+	findings := d.runDetectors(ctx, tx, *user, facts)
+	if f := scoreAmountAnomaly(d.anomaly, profile, tx.Amount); f != nil {
+		findings = append(findings, *f)
 	}
 
-	var alert domain.FraudAlert
-	cachedAlert, _ := d.cache.GetRiskCache(ctx, tx.UserID, tx.Merchant)
+	ai := d.analyze(ctx, tx, *user, facts)
+	verdict, score, decision := decide(d.rules, d.scorer, facts, findings, ai.alert, vel)
+	reasons := verdict.explain(d.scorer, ai, vel)
+	if ai.failed {
+		var fallback domain.Reason
+		decision, fallback = d.degraded.apply(facts, verdict, score, decision)
+		reasons = append(reasons, fallback)
+		slog.Warn("Decided in degraded mode", "tx_id", tx.ID, "policy", fallback.Code, "decision", decision)
+	}
+	out := outcome{
+		decision: decision,
+		score:    score,
+		reasons:  reasons,
+		tags:     verdict.tags,
+		ipFlags:  ipFlags(facts),
+		original: original,
+		ai:       ai.alert,
+		degraded: ai.failed,
+	}
+	if err := d.save(ctx, tx, out); err != nil {
+		slog.Error("Failed to save fraud event", "err", err)
+	}
+	if err := d.features.Log(ctx, tx, facts, decision, score); err != nil {
+		slog.Warn("Failed to log features", "tx_id", tx.ID, "err", err)
+	}
+	if decision == domain.DecisionReview && d.cases != nil {
+		if err := d.cases.Open(ctx, &domain.ReviewCase{
+			TransactionID: tx.ID,
+			UserID:        tx.UserID,
+			Decision:      decision,
+			RiskScore:     score,
+			Amount:        tx.Amount,
+			Currency:      tx.Currency,
+			Merchant:      tx.Merchant,
+			Reason:        out.reasons.Summary(decision),
+		}); err != nil {
+			slog.Error("Failed to open review case", "tx_id", tx.ID, "err", err)
+		}
+	}
+	d.runShadows(ctx, tx, *user, facts, findings, ai, vel, out)
 
-	if cachedAlert != nil {
-		alert = *cachedAlert
-	} else {
-		var err error
-		alert, err = d.aiClient.Analyze(ctx, tx, *user)
-		if err != nil {
-			slog.Error("AI Analysis failed", "err", err)
-			alert = domain.FraudAlert{
-				IsBlocked: false,
-				Reason:    "AI Service Error - FailSafe Active",
+	d.features.Record(ctx, tx, decision, online)
+	d.recordDetectors(ctx, tx, decision)
+	if decision != domain.DecisionBlock {
+		if d.history != nil {
+			if err := d.history.Record(ctx, tx); err != nil {
+				slog.Warn("Failed to record entity history", "user_id", tx.UserID, "err", err)
 			}
-		} else {
-			_ = d.cache.SetRiskCache(ctx, tx.UserID, tx.Merchant, alert)
+		}
+	}
+	if d.risk != nil {
+		if err := d.risk.ApplyDecision(ctx, stored, tx.ID, decision); err != nil {
+			slog.Warn("Failed to update user risk", "user_id", tx.UserID, "err", err)
+		}
+	}
+	// Cached after the risk update, which invalidates the user's earlier
+	// verdicts, so the verdict is kept under the risk it leaves behind.
+	if d.riskCache != nil && ai.source == domain.ReasonSourceAI && ai.code == "AI_VERDICT" {
+		if err := d.riskCache.Set(ctx, tx, ai.alert, decision); err != nil {
+			slog.Warn("Failed to cache AI verdict", "tx_id", tx.ID, "err", err)
+		}
+	}
+	if decision == domain.DecisionBlock && d.bans != nil {
+		if _, err := d.bans.EvaluateAutoBan(ctx, tx.UserID); err != nil {
+			slog.Error("Auto-ban evaluation failed", "user_id", tx.UserID, "err", err)
 		}
 	}
 
-	finalBlocked := isVelocityFraud || alert.IsBlocked
-	reason := alert.Reason
+	return d.publish(ctx, tx, out)
+}
 
-	if isVelocityFraud {
-		if alert.IsBlocked {
-			reason = "[Velocity Block] + " + alert.Reason
-		} else {
-			reason = "[Velocity Block] User exceeded transaction frequency limit"
-		}
+type outcome struct {
+	decision domain.Decision
+	score    int
+	reasons  domain.Reasons
+	tags     []string
+	ipFlags  []string
+	original domain.Transaction
+	ai       domain.FraudAlert
+	degraded bool
+}
+
+func (d *FraudDetector) detectBanned(ctx context.Context, tx, original domain.Transaction, user *domain.User) error {
+	message := "Account is banned"
+	if user.BannedBy != "" {
+		message += " by " + user.BannedBy
+	}
+	if user.BanReason != "" {
+		message += ": " + user.BanReason
 	}
 
-	event := &domain.FraudEvent{
-		TransactionID: tx.ID,
-		UserID:        tx.UserID,
-		Merchant:      tx.Merchant,
-		Amount:        tx.Amount,
-		Location:      tx.Location,
-		IsBlocked:     finalBlocked,
-		AIReason:      reason,
+	out := outcome{
+		decision: domain.DecisionBlock,
+		score:    100,
+		reasons: domain.Reasons{{
+			Code:    "USER_BANNED",
+			Source:  domain.ReasonSourceBan,
+			Score:   100,
+			Message: message,
+		}},
+		original: original,
 	}
-	if err := d.repo.SaveFraudEvent(ctx, event); err != nil {
+	if err := d.save(ctx, tx, out); err != nil {
 		slog.Error("Failed to save fraud event", "err", err)
 	}
+	return d.publish(ctx, tx, out)
+}
 
-	_ = d.cache.IncrementVelocity(ctx, tx.UserID, tx.Location)
+func (d *FraudDetector) save(ctx context.Context, tx domain.Transaction, out outcome) error {
+	return d.repo.SaveFraudEvent(ctx, &domain.FraudEvent{
+		TransactionID:    tx.ID,
+		UserID:           tx.UserID,
+		Merchant:         tx.Merchant,
+		Amount:           tx.Amount,
+		Currency:         tx.Currency,
+		OriginalAmount:   out.original.Amount,
+		OriginalCurrency: out.original.Currency,
+		Location:         tx.Location,
+		IP:               tx.IP,
+		DeviceID:         tx.DeviceID,
+		IPFlags:          out.ipFlags,
+		IsBlocked:        out.decision == domain.DecisionBlock,
+		Decision:         out.decision,
+		RiskScore:        out.score,
+		AIBlocked:        out.ai.IsBlocked,
+		AIRiskScore:      out.ai.RiskScore,
+		AIReason:         out.reasons.Summary(out.decision),
+		Degraded:         out.degraded,
+		Reasons:          out.reasons,
+		Tags:             out.tags,
+	})
+}
 
-	outAlert := domain.FraudAlert{
-		TransactionID: tx.ID,
-		IsBlocked:     finalBlocked,
-		Reason:        reason,
-		Amount:        tx.Amount,
-		Location:      tx.Location,
-		Merchant:      tx.Merchant,
+func (d *FraudDetector) publish(ctx context.Context, tx domain.Transaction, out outcome) error {
+	alert := domain.FraudAlert{
+		TransactionID:    tx.ID,
+		IsBlocked:        out.decision == domain.DecisionBlock,
+		Decision:         out.decision,
+		RiskScore:        out.score,
+		Reason:           out.reasons.Summary(out.decision),
+		Reasons:          out.reasons,
+		Amount:           tx.Amount,
+		Currency:         tx.Currency,
+		OriginalAmount:   out.original.Amount,
+		OriginalCurrency: out.original.Currency,
+		Location:         tx.Location,
+		Merchant:         tx.Merchant,
+		IP:               tx.IP,
+		IPFlags:          out.ipFlags,
+		Tags:             out.tags,
+		Degraded:         out.degraded,
 	}
 
-	if err := d.publisher.Publish(ctx, outAlert); err != nil {
+	if err := d.publisher.Publish(ctx, alert); err != nil {
 		slog.Error("Failed to publish alert to kafka", "err", err)
 		return err
 	}
-
 	return nil
 }
+
+func (d *FraudDetector) analyze(ctx context.Context, tx domain.Transaction, user domain.User, facts Facts) aiVerdict {
+	if d.trustSkip && facts["trust.all"] == true {
+		return aiVerdict{
+			alert:  domain.FraudAlert{Reason: "AI skipped: merchant, location and IP are trusted"},
+			source: domain.ReasonSourceAI,
+			code:   "AI_SKIPPED",
+		}
+	}
+
+	if d.riskCache != nil {
+		cached, err := d.riskCache.Get(ctx, tx)
+		if err != nil {
+			slog.Warn("Failed to load cached AI verdict", "tx_id", tx.ID, "err", err)
+		}
+		if cached != nil {
+			return aiVerdict{alert: *cached, source: domain.ReasonSourceCache, code: "AI_VERDICT"}
+		}
+	}
+
+	alert, err := d.aiClient.Analyze(ctx, tx, user)
+	if err != nil {
+		slog.Error("AI Analysis failed", "err", err)
+		return aiVerdict{
+			alert:  domain.FraudAlert{Reason: "AI unavailable, degraded mode"},
+			source: domain.ReasonSourceAI,
+			code:   "AI_UNAVAILABLE",
+			failed: true,
+		}
+	}
+	return aiVerdict{alert: alert, source: domain.ReasonSourceAI, code: "AI_VERDICT"}
+}
+
+func (d *FraudDetector) normalize(tx domain.Transaction) domain.Transaction {
+	if d.fx == nil {
+		return tx
+	}
+	normalized, err := d.fx.Normalize(tx)
+	if err != nil {
+		slog.Warn("Amount left in original currency", "tx_id", tx.ID, "err", err)
+		return tx
+	}
+	return normalized
+}
+
+// loadNovelty returns nil when no history is configured or it cannot be
+// read, so the AI is not told that everything is new.
+func (d *FraudDetector) loadNovelty(ctx context.Context, tx domain.Transaction) *domain.Novelty {
+	if d.history == nil {
+		return nil
+	}
+	n, err := d.history.Novelty(ctx, tx)
+	if err != nil {
+		slog.Warn("Failed to load entity history", "user_id", tx.UserID, "err", err)
+		return nil
+	}
+	return &n
+}
+
+func (d *FraudDetector) runDetectors(ctx context.Context, tx domain.Transaction, user domain.User, facts Facts) []Finding {
+	var findings []Finding
+	for _, det := range d.detectors {
+		found, err := det.Evaluate(ctx, tx, user, facts)
+		if err != nil {
+			slog.Warn("Detector failed", "detector", det.Name(), "tx_id", tx.ID, "err", err)
+			continue
+		}
+		findings = append(findings, found...)
+	}
+	return findings
+}
+
+func (d *FraudDetector) recordDetectors(ctx context.Context, tx domain.Transaction, decision domain.Decision) {
+	for _, det := range d.detectors {
+		rec, ok := det.(Recorder)
+		if !ok {
+			continue
+		}
+		if err := rec.Record(ctx, tx, decision); err != nil {
+			slog.Warn("Detector record failed", "detector", det.Name(), "tx_id", tx.ID, "err", err)
+		}
+	}
+}
+
+// decide combines rule hits, detector findings, velocity and the AI verdict
+// into a score and decision. Live and shadow evaluations share it.
+func decide(rules *RuleEngine, scorer *Scorer, facts Facts, findings []Finding, alert domain.FraudAlert, velocity int) (ruleVerdict, int, domain.Decision) {
+	verdict := summarizeRuleHits(rules.Evaluate(facts))
+	verdict.addFindings(findings)
+
+	signals := verdict.signals()
+	signals[SourceVelocity] = scorer.VelocitySignal(velocity)
+	signals[SourceAI] = aiSignal(alert)
+
+	score := scorer.Score(signals)
+	return verdict, score, scorer.Decide(score, verdict.floor(), merchantTier(facts))
+}
+
+type ruleVerdict struct {
+	blocked  bool
+	review   bool
+	score    int
+	tags     []string
+	hits     []RuleHit
+	findings []Finding
+}
+
+func summarizeRuleHits(hits []RuleHit) ruleVerdict {
+	var v ruleVerdict
+	for _, h := range hits {
+		switch h.Action {
+		case RuleActionBlock:
+			v.blocked = true
+			v.hits = append(v.hits, h)
+		case RuleActionReview:
+			v.review = true
+			v.hits = append(v.hits, h)
+		case RuleActionScore:
+			v.score += h.Score
+			v.hits = append(v.hits, h)
+		case RuleActionTag:
+			v.tags = append(v.tags, h.Tag)
+		}
+	}
+	return v
+}
+
+func (v *ruleVerdict) addFindings(findings []Finding) {
+	v.findings = append(v.findings, findings...)
+}
+
+func (v ruleVerdict) floor() domain.Decision {
+	floor := domain.DecisionAllow
+	switch {
+	case v.blocked:
+		floor = domain.DecisionBlock
+	case v.review:
+		floor = domain.DecisionReview
+	}
+	for _, f := range v.findings {
+		floor = domain.MaxDecision(floor, f.Floor)
+	}
+	return floor
+}
+
+func (v ruleVerdict) signals() map[string]float64 {
+	signals := map[string]float64{SourceRules: float64(v.score)}
+	if v.blocked {
+		signals[SourceRules] = 100
+	}
+	for _, f := range v.findings {
+		signals[f.Detector] = max(signals[f.Detector], f.Score)
+	}
+	return signals
+}
+
+// aiVerdict is the AI's opinion on a transaction, where it came from and
+// the reason code it is reported under. failed marks an AI outage, decided
+// by the degraded policy.
+type aiVerdict struct {
+	alert  domain.FraudAlert
+	source domain.ReasonSource
+	code   string
+	failed bool
+}
+
+// explain lists everything that contributed to the decision, in the order
+// rules, detectors, velocity and AI, each with the points it added to the
+// score. The AI's opinion is left out when a block rule overrode it.
+func (v ruleVerdict) explain(scorer *Scorer, ai aiVerdict, velocity int) domain.Reasons {
+	var reasons domain.Reasons
+	for _, h := range v.hits {
+		var points int
+		switch h.Action {
+		case RuleActionBlock:
+			points = scorer.Contribution(SourceRules, 100)
+		case RuleActionScore:
+			points = scorer.Contribution(SourceRules, float64(h.Score))
+		}
+		reasons = append(reasons, domain.Reason{
+			Code:    h.RuleID,
+			Source:  domain.ReasonSourceRule,
+			Name:    h.Name,
+			Score:   points,
+			Message: h.Message,
+		})
+	}
+	for _, f := range v.findings {
+		reasons = append(reasons, domain.Reason{
+			Code:    f.Code,
+			Source:  domain.ReasonSource(f.Detector),
+			Score:   scorer.Contribution(f.Detector, f.Score),
+			Message: f.Message,
+		})
+	}
+	if points := scorer.Contribution(SourceVelocity, scorer.VelocitySignal(velocity)); points > 0 {
+		reasons = append(reasons, domain.Reason{
+			Code:    "VELOCITY",
+			Source:  domain.ReasonSourceVelocity,
+			Score:   points,
+			Message: fmt.Sprintf("%d recent transactions", velocity),
+		})
+	}
+	if v.floor() != domain.DecisionBlock || ai.alert.IsBlocked {
+		reasons = append(reasons, domain.Reason{
+			Code:    ai.code,
+			Source:  ai.source,
+			Score:   scorer.Contribution(SourceAI, aiSignal(ai.alert)),
+			Message: ai.alert.Reason,
+		})
+	}
+	return reasons
+}
+
+func buildFacts(tx, original domain.Transaction, user domain.User) Facts {
+	return Facts{
+		"tx.amount":            tx.Amount,
+		"tx.currency":          tx.Currency,
+		"tx.original_amount":   original.Amount,
+		"tx.original_currency": original.Currency,
+		"tx.merchant":          tx.Merchant,
+		"tx.location":          tx.Location,
+		"tx.ip":                tx.IP,
+		"user.id":              user.ID,
+		"user.risk_score":      user.RiskScore,
+		"user.is_banned":       user.IsBanned,
+		"user.max_tx":          user.MaxTx,
+		"user.avg_tx":          user.AvgTx,
+	}
+}
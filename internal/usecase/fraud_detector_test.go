@@ -2,15 +2,27 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/tokyosplif/fraud-core/internal/domain"
 )
 
-type mockRepo struct{}
+type mockRepo struct {
+	amounts []float64
+	user    *domain.User
+	events  []*domain.FraudEvent
+}
 
 func (m *mockRepo) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	if m.user != nil {
+		u := *m.user
+		return &u, nil
+	}
 	return &domain.User{ID: "user-1", RiskScore: 10}, nil
 }
 
@@ -19,44 +31,73 @@ func (m *mockRepo) CreateUser(ctx context.Context, user *domain.User) error {
 }
 
 func (m *mockRepo) SaveFraudEvent(ctx context.Context, event *domain.FraudEvent) error {
+	m.events = append(m.events, event)
 	return nil
 }
 
-func (m *mockRepo) GetUserStats(ctx context.Context, userID string) (float64, float64, error) {
-	return 1000, 200, nil
+func (m *mockRepo) GetUserAmounts(ctx context.Context, userID string, limit int) ([]float64, error) {
+	return m.amounts, nil
 }
 
 type mockCache struct {
-	velocity int
+	activity []domain.Activity
 }
 
-func (m *mockCache) GetVelocity(ctx context.Context, userID string) (int, error) {
-	return m.velocity, nil
+func (m *mockCache) GetActivity(ctx context.Context, userID string, since time.Time) ([]domain.Activity, error) {
+	return m.activity, nil
 }
 
-func (m *mockCache) IncrementVelocity(ctx context.Context, userID, location string) error {
-	return nil
+func (m *mockCache) GetActivityStats(ctx context.Context, userID string, now time.Time, windows []time.Duration) ([]domain.ActivityStats, error) {
+	stats := make([]domain.ActivityStats, len(windows))
+	for i, w := range windows {
+		stats[i] = domain.SummarizeActivity(m.activity, now.Add(-w), now)
+	}
+	return stats, nil
 }
 
-func (m *mockCache) GetUserStats(ctx context.Context, userID string) (float64, float64, error) {
-	return 0, 0, nil
+func (m *mockCache) RecordActivity(ctx context.Context, userID string, activity domain.Activity, retention time.Duration) error {
+	m.activity = append(m.activity, activity)
+	return nil
 }
 
-func (m *mockCache) SetUserStats(ctx context.Context, userID string, maxAmount, avgAmount float64) error {
-	return nil
+func recentActivity(n int, location string) []domain.Activity {
+	activity := make([]domain.Activity, n)
+	for i := range activity {
+		activity[i] = domain.Activity{
+			TransactionID: fmt.Sprintf("tx-prev-%d", i),
+			Amount:        100,
+			Location:      location,
+			At:            time.Now().Add(-time.Duration(n-i) * time.Second),
+		}
+	}
+	return activity
 }
 
-func (m *mockCache) GetRiskCache(ctx context.Context, userID, merchant string) (*domain.FraudAlert, error) {
+func (m *mockCache) GetAmountProfile(ctx context.Context, userID string) (*domain.AmountProfile, error) {
 	return nil, nil
 }
 
-func (m *mockCache) SetRiskCache(ctx context.Context, userID, merchant string, alert domain.FraudAlert) error {
+func (m *mockCache) SetAmountProfile(ctx context.Context, userID string, profile *domain.AmountProfile) error {
 	return nil
 }
 
-type mockAI struct{}
+type mockAI struct {
+	blocked bool
+	fail    bool
+	calls   int
+}
 
 func (m *mockAI) Analyze(ctx context.Context, tx domain.Transaction, user domain.User) (domain.FraudAlert, error) {
+	m.calls++
+	if m.fail {
+		return domain.FraudAlert{}, errors.New("ai-risk-engine unavailable")
+	}
+	if m.blocked {
+		return domain.FraudAlert{
+			IsBlocked: true,
+			Reason:    "Geo mismatch with user history",
+		}, nil
+	}
 	return domain.FraudAlert{
 		IsBlocked: false,
 		Reason:    "Looks like a normal transaction",
@@ -74,7 +115,7 @@ func (m *mockPublisher) Publish(ctx context.Context, alert domain.FraudAlert) er
 
 func TestFraudDetector_VelocityBlockOverridesAI(t *testing.T) {
 	repo := &mockRepo{}
-	cache := &mockCache{velocity: 15}
+	cache := &mockCache{activity: recentActivity(15, "Kyiv, Ukraine")}
 	aiClient := &mockAI{}
 	publisher := &mockPublisher{}
 
@@ -107,7 +148,7 @@ func TestFraudDetector_VelocityBlockOverridesAI(t *testing.T) {
 
 func TestFraudDetector_NormalTransactionIsAllowed(t *testing.T) {
 	repo := &mockRepo{}
-	cache := &mockCache{velocity: 2}
+	cache := &mockCache{activity: recentActivity(2, "Kyiv, Ukraine")}
 	aiClient := &mockAI{}
 	publisher := &mockPublisher{}
 
@@ -130,4 +171,139 @@ func TestFraudDetector_NormalTransactionIsAllowed(t *testing.T) {
 	if result.IsBlocked {
 		t.Errorf("Expected normal transaction to be allowed, but it was blocked")
 	}
+
+	if result.Decision != domain.DecisionAllow {
+		t.Errorf("Expected decision ALLOW, got: %s (score %d)", result.Decision, result.RiskScore)
+	}
+}
+
+func TestFraudDetector_AIBlockProducesBlockDecision(t *testing.T) {
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(&mockAI{blocked: true}, &mockRepo{}, &mockCache{}, publisher)
+
+	if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-777", UserID: "user-1", Amount: 99999}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	result := publisher.PublishedAlert
+	if result.Decision != domain.DecisionBlock || !result.IsBlocked {
+		t.Errorf("Expected BLOCK, got: %s (score %d)", result.Decision, result.RiskScore)
+	}
+	if result.RiskScore < 70 {
+		t.Errorf("Expected risk score above block threshold, got: %d", result.RiskScore)
+	}
+}
+
+func TestFraudDetector_BorderlineScoreGoesToReview(t *testing.T) {
+	engine, err := NewRuleEngine([]Rule{{
+		ID:         "AMT-001",
+		Name:       "Large Amount",
+		Message:    "Amount exceeds 10000",
+		Conditions: []Condition{{Field: "tx.amount", Op: OpGt, Value: 10000}},
+		Action:     RuleActionScore,
+		Score:      50,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(&mockAI{}, &mockRepo{}, &mockCache{}, publisher, WithRuleEngine(engine))
+
+	if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-666", UserID: "user-1", Amount: 15000}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	result := publisher.PublishedAlert
+	if result.Decision != domain.DecisionReview {
+		t.Errorf("Expected REVIEW, got: %s (score %d)", result.Decision, result.RiskScore)
+	}
+	if result.IsBlocked {
+		t.Errorf("Expected review transaction not to be blocked")
+	}
+	if !strings.Contains(result.Reason, "AMT-001") {
+		t.Errorf("Expected reason to reference AMT-001, got: %s", result.Reason)
+	}
+}
+
+func TestFraudDetector_ReasonsExplainScoreContributions(t *testing.T) {
+	engine, err := NewRuleEngine([]Rule{{
+		ID:         "AMT-001",
+		Name:       "Large Amount",
+		Message:    "Amount exceeds 10000",
+		Conditions: []Condition{{Field: "tx.amount", Op: OpGt, Value: 10000}},
+		Action:     RuleActionScore,
+		Score:      50,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &mockRepo{}
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(&mockAI{fail: true}, repo, &mockCache{activity: recentActivity(5, "Kyiv, Ukraine")}, publisher, WithRuleEngine(engine))
+
+	if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-665", UserID: "user-1", Amount: 15000}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	want := []domain.Reason{
+		{Code: "AMT-001", Source: domain.ReasonSourceRule, Name: "Large Amount", Score: 50, Message: "Amount exceeds 10000"},
+		{Code: "VELOCITY", Source: domain.ReasonSourceVelocity, Score: 15, Message: "5 recent transactions"},
+		{Code: "AI_UNAVAILABLE", Source: domain.ReasonSourceAI, Message: "AI unavailable, degraded mode"},
+		{Code: "DEG-002", Source: domain.ReasonSourceDegraded, Message: "Large amount, fallback REVIEW while the AI is unavailable"},
+	}
+	result := publisher.PublishedAlert
+	if !slices.Equal(result.Reasons, want) {
+		t.Fatalf("Unexpected reasons:\n got %+v\nwant %+v", result.Reasons, want)
+	}
+	if result.Reason != result.Reasons.Summary(result.Decision) {
+		t.Errorf("Expected summary to be rendered from reasons, got: %s", result.Reason)
+	}
+	if len(repo.events) != 1 || !slices.Equal(repo.events[0].Reasons, want) {
+		t.Errorf("Expected reasons to be stored on the event")
+	}
+}
+
+func TestFraudDetector_AmountOutlierScoredWhenAIFails(t *testing.T) {
+	repo := &mockRepo{amounts: []float64{350, 420, 380, 450, 400, 390, 410, 430}}
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(&mockAI{fail: true}, repo, &mockCache{}, publisher)
+
+	if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-555", UserID: "user-3", Amount: 99999}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	result := publisher.PublishedAlert
+	if result.Decision != domain.DecisionBlock {
+		t.Errorf("Expected outlier amount to be blocked on its own, got: %s (score %d)", result.Decision, result.RiskScore)
+	}
+	if !strings.Contains(result.Reason, "AMOUNT_OUTLIER") {
+		t.Errorf("Expected AMOUNT_OUTLIER in reason, got: %s", result.Reason)
+	}
+}
+
+func TestFraudDetector_BannedUserShortCircuits(t *testing.T) {
+	repo := &mockRepo{user: &domain.User{ID: "user-2", IsBanned: true, BannedBy: "analyst@bank", BanReason: "mule account"}}
+	ai := &mockAI{}
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(ai, repo, &mockCache{}, publisher)
+
+	if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-444", UserID: "user-2", Amount: 10}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	result := publisher.PublishedAlert
+	if result.Decision != domain.DecisionBlock {
+		t.Errorf("Expected banned user to be blocked, got: %s", result.Decision)
+	}
+	if !strings.HasPrefix(result.Reason, "[USER_BANNED]") {
+		t.Errorf("Expected USER_BANNED reason, got: %s", result.Reason)
+	}
+	if ai.calls != 0 {
+		t.Errorf("Expected AI not to be called for banned user, got %d calls", ai.calls)
+	}
+	if len(repo.events) != 1 || repo.events[0].Decision != domain.DecisionBlock {
+		t.Errorf("Expected blocked event to be persisted, got: %+v", repo.events)
+	}
 }
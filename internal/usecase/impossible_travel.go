@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+const (
+	SourceImpossibleTravel = "impossible_travel"
+
+	sameAreaKm       = 50.0
+	minTravelElapsed = time.Minute
+)
+
+type GeoResolver interface {
+	Resolve(location string) (domain.GeoPoint, bool)
+}
+
+type GeoStore interface {
+	GetLastPosition(ctx context.Context, userID string) (*domain.GeoPosition, error)
+	SetLastPosition(ctx context.Context, userID string, pos domain.GeoPosition) error
+}
+
+type ImpossibleTravelDetector struct {
+	resolver    GeoResolver
+	store       GeoStore
+	maxSpeedKmh float64
+}
+
+func NewImpossibleTravelDetector(resolver GeoResolver, store GeoStore, maxSpeedKmh float64) *ImpossibleTravelDetector {
+	return &ImpossibleTravelDetector{
+		resolver:    resolver,
+		store:       store,
+		maxSpeedKmh: maxSpeedKmh,
+	}
+}
+
+func (d *ImpossibleTravelDetector) Name() string {
+	return SourceImpossibleTravel
+}
+
+func (d *ImpossibleTravelDetector) Evaluate(ctx context.Context, tx domain.Transaction, _ domain.User, facts Facts) ([]Finding, error) {
+	current, ok := d.resolver.Resolve(tx.Location)
+	if !ok {
+		return nil, nil
+	}
+
+	last, err := d.store.GetLastPosition(ctx, tx.UserID)
+	if err != nil || last == nil {
+		return nil, err
+	}
+
+	distance := last.DistanceKm(current)
+	elapsed := txTime(tx).Sub(last.At)
+	if elapsed < minTravelElapsed {
+		elapsed = minTravelElapsed
+	}
+
+	speed := 0.0
+	if distance > sameAreaKm {
+		speed = distance / elapsed.Hours()
+	}
+
+	facts["features.travel_distance_km"] = distance
+	facts["features.travel_speed_kmh"] = speed
+
+	if speed <= d.maxSpeedKmh {
+		return nil, nil
+	}
+
+	return []Finding{{
+		Detector: SourceImpossibleTravel,
+		Code:     "IMPOSSIBLE_TRAVEL",
+		Score:    100,
+		Floor:    domain.DecisionReview,
+		Message: fmt.Sprintf("%s -> %s: %.0f km in %s (%.0f km/h)",
+			last.Location, tx.Location, distance, elapsed.Round(time.Second), speed),
+	}}, nil
+}
+
+func (d *ImpossibleTravelDetector) Record(ctx context.Context, tx domain.Transaction, decision domain.Decision) error {
+	if decision == domain.DecisionBlock {
+		return nil
+	}
+	point, ok := d.resolver.Resolve(tx.Location)
+	if !ok {
+		return nil
+	}
+	return d.store.SetLastPosition(ctx, tx.UserID, domain.GeoPosition{
+		GeoPoint: point,
+		Location: tx.Location,
+		At:       txTime(tx),
+	})
+}
+
+func txTime(tx domain.Transaction) time.Time {
+	if tx.Timestamp.IsZero() {
+		return time.Now()
+	}
+	return tx.Timestamp
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type stubResolver map[string]domain.GeoPoint
+
+func (r stubResolver) Resolve(location string) (domain.GeoPoint, bool) {
+	p, ok := r[location]
+	return p, ok
+}
+
+type memoryGeoStore struct {
+	positions map[string]domain.GeoPosition
+}
+
+func (m *memoryGeoStore) GetLastPosition(ctx context.Context, userID string) (*domain.GeoPosition, error) {
+	pos, ok := m.positions[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &pos, nil
+}
+
+func (m *memoryGeoStore) SetLastPosition(ctx context.Context, userID string, pos domain.GeoPosition) error {
+	m.positions[userID] = pos
+	return nil
+}
+
+var testCities = stubResolver{
+	"Kyiv, Ukraine":  {Lat: 50.4501, Lon: 30.5234, Country: "Ukraine"},
+	"Lviv, Ukraine":  {Lat: 49.8397, Lon: 24.0297, Country: "Ukraine"},
+	"Lagos, Nigeria": {Lat: 6.5244, Lon: 3.3792, Country: "Nigeria"},
+}
+
+func TestImpossibleTravel_LagosAfterKyivIsBlockedWithoutAI(t *testing.T) {
+	store := &memoryGeoStore{positions: map[string]domain.GeoPosition{}}
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(&mockAI{fail: true}, &mockRepo{}, &mockCache{}, publisher,
+		WithDetectors(NewImpossibleTravelDetector(testCities, store, 1000)),
+	)
+
+	start := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	txs := []domain.Transaction{
+		{ID: "tx-1", UserID: "user-1", Amount: 9000, Location: "Kyiv, Ukraine", Timestamp: start},
+		{ID: "tx-2", UserID: "user-1", Amount: 99999, Location: "Lagos, Nigeria", Timestamp: start.Add(5 * time.Minute)},
+	}
+
+	for _, tx := range txs {
+		if err := detector.Detect(context.Background(), tx); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	result := publisher.PublishedAlert
+	if result.Decision != domain.DecisionBlock {
+		t.Errorf("Expected BLOCK for Kyiv -> Lagos in 5 minutes, got: %s (score %d)", result.Decision, result.RiskScore)
+	}
+	if !strings.Contains(result.Reason, "IMPOSSIBLE_TRAVEL") {
+		t.Errorf("Expected IMPOSSIBLE_TRAVEL in reason, got: %s", result.Reason)
+	}
+	if store.positions["user-1"].Location != "Kyiv, Ukraine" {
+		t.Errorf("Expected blocked transaction not to move the last known position, got: %s", store.positions["user-1"].Location)
+	}
+}
+
+func TestImpossibleTravel_PlausibleTripIsNotFlagged(t *testing.T) {
+	start := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	store := &memoryGeoStore{positions: map[string]domain.GeoPosition{
+		"user-3": {GeoPoint: testCities["Kyiv, Ukraine"], Location: "Kyiv, Ukraine", At: start},
+	}}
+	d := NewImpossibleTravelDetector(testCities, store, 1000)
+
+	facts := Facts{}
+	tx := domain.Transaction{UserID: "user-3", Location: "Lviv, Ukraine", Timestamp: start.Add(6 * time.Hour)}
+
+	findings, err := d.Evaluate(context.Background(), tx, domain.User{}, facts)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("Expected no finding for a 6h Kyiv -> Lviv trip, got: %+v", findings)
+	}
+	if speed, _ := facts["features.travel_speed_kmh"].(float64); speed <= 0 || speed > 200 {
+		t.Errorf("Expected ground-speed feature, got: %v", facts["features.travel_speed_kmh"])
+	}
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+const SourceIPIntel = "ip_intel"
+
+type IPReputationSource interface {
+	Lookup(addr netip.Addr) domain.IPReputation
+}
+
+type IPVelocityStore interface {
+	RecordIPUse(ctx context.Context, ip, userID, txID string, at time.Time, window time.Duration) error
+	GetIPUsers(ctx context.Context, ip string, since time.Time) ([]string, error)
+	CountIPTransactions(ctx context.Context, ip string, since time.Time) (int64, error)
+}
+
+type IPIntelConfig struct {
+	Window      time.Duration
+	MaxUsers    int
+	MaxTxPerWin int
+}
+
+func DefaultIPIntelConfig() IPIntelConfig {
+	return IPIntelConfig{
+		Window:      time.Hour,
+		MaxUsers:    5,
+		MaxTxPerWin: 100,
+	}
+}
+
+type IPIntelDetector struct {
+	reputation IPReputationSource
+	store      IPVelocityStore
+	cfg        IPIntelConfig
+}
+
+func NewIPIntelDetector(reputation IPReputationSource, store IPVelocityStore, cfg IPIntelConfig) *IPIntelDetector {
+	return &IPIntelDetector{reputation: reputation, store: store, cfg: cfg}
+}
+
+func (d *IPIntelDetector) Name() string {
+	return SourceIPIntel
+}
+
+func (d *IPIntelDetector) Evaluate(ctx context.Context, tx domain.Transaction, _ domain.User, facts Facts) ([]Finding, error) {
+	if tx.IP == "" {
+		return nil, nil
+	}
+	addr, err := netip.ParseAddr(tx.IP)
+	if err != nil {
+		facts["features.ip_flags"] = []string{domain.IPFlagInvalid}
+		return nil, nil
+	}
+
+	rep := d.reputation.Lookup(addr.Unmap())
+	var flags []string
+	var findings []Finding
+
+	if rep.Denied {
+		flags = append(flags, domain.IPFlagDenied)
+		findings = append(findings, d.finding("IP_DENYLIST", 100, domain.DecisionBlock,
+			fmt.Sprintf("IP %s is on the deny list", tx.IP)))
+	}
+	if rep.Allowed && !rep.Denied {
+		facts["features.ip_flags"] = []string{domain.IPFlagAllowed}
+		return nil, nil
+	}
+	if rep.Tor {
+		flags = append(flags, domain.IPFlagTor)
+		findings = append(findings, d.finding("IP_TOR", 70, "",
+			fmt.Sprintf("IP %s is a known TOR exit node", tx.IP)))
+	}
+	if rep.Proxy {
+		flags = append(flags, domain.IPFlagProxy)
+		findings = append(findings, d.finding("IP_PROXY", 40, "",
+			fmt.Sprintf("IP %s belongs to a known proxy/hosting range", tx.IP)))
+	}
+
+	// Reputation comes from local lists, so a velocity store outage must not
+	// cost its findings.
+	others, txCount, err := d.velocity(ctx, tx)
+	if err != nil {
+		slog.Warn("IP velocity unavailable", "ip", tx.IP, "tx_id", tx.ID, "err", err)
+	} else {
+		facts["features.ip_users_window"] = others + 1
+		facts["features.ip_tx_window"] = txCount
+
+		if others+1 > d.cfg.MaxUsers {
+			flags = append(flags, domain.IPFlagShared)
+			findings = append(findings, d.finding("IP_SHARED", clamp(float64(others+1)*100/float64(d.cfg.MaxUsers*2), 50, 100), "",
+				fmt.Sprintf("IP %s used by %d accounts within %s", tx.IP, others+1, d.cfg.Window)))
+		}
+		if d.cfg.MaxTxPerWin > 0 && txCount > int64(d.cfg.MaxTxPerWin) {
+			findings = append(findings, d.finding("IP_VELOCITY", 50, "",
+				fmt.Sprintf("IP %s made %d transactions within %s", tx.IP, txCount, d.cfg.Window)))
+		}
+	}
+
+	facts["features.ip_flags"] = flags
+	facts["features.ip_denied"] = rep.Denied
+	facts["features.ip_proxy"] = rep.Proxy
+	facts["features.ip_tor"] = rep.Tor
+	return findings, nil
+}
+
+// velocity returns how many other users and how many transactions used the
+// IP within the window.
+func (d *IPIntelDetector) velocity(ctx context.Context, tx domain.Transaction) (int, int64, error) {
+	since := txTime(tx).Add(-d.cfg.Window)
+	users, err := d.store.GetIPUsers(ctx, tx.IP, since)
+	if err != nil {
+		return 0, 0, err
+	}
+	others := 0
+	for _, u := range users {
+		if u != tx.UserID {
+			others++
+		}
+	}
+	txCount, err := d.store.CountIPTransactions(ctx, tx.IP, since)
+	if err != nil {
+		return 0, 0, err
+	}
+	return others, txCount, nil
+}
+
+func (d *IPIntelDetector) Record(ctx context.Context, tx domain.Transaction, _ domain.Decision) error {
+	if tx.IP == "" {
+		return nil
+	}
+	return d.store.RecordIPUse(ctx, tx.IP, tx.UserID, tx.ID, txTime(tx), d.cfg.Window)
+}
+
+func (d *IPIntelDetector) finding(code string, score float64, floor domain.Decision, msg string) Finding {
+	return Finding{
+		Detector: SourceIPIntel,
+		Code:     code,
+		Score:    score,
+		Floor:    floor,
+		Message:  msg,
+	}
+}
+
+func ipFlags(facts Facts) []string {
+	flags, _ := facts["features.ip_flags"].([]string)
+	return flags
+}
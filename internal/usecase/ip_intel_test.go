@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type stubReputation map[string]domain.IPReputation
+
+func (s stubReputation) Lookup(addr netip.Addr) domain.IPReputation {
+	return s[addr.String()]
+}
+
+type memoryIPStore struct {
+	users map[string]map[string]time.Time
+	txs   map[string][]time.Time
+}
+
+func newMemoryIPStore() *memoryIPStore {
+	return &memoryIPStore{users: map[string]map[string]time.Time{}, txs: map[string][]time.Time{}}
+}
+
+func (m *memoryIPStore) RecordIPUse(ctx context.Context, ip, userID, txID string, at time.Time, window time.Duration) error {
+	if m.users[ip] == nil {
+		m.users[ip] = map[string]time.Time{}
+	}
+	m.users[ip][userID] = at
+	m.txs[ip] = append(m.txs[ip], at)
+	return nil
+}
+
+func (m *memoryIPStore) GetIPUsers(ctx context.Context, ip string, since time.Time) ([]string, error) {
+	var out []string
+	for u, at := range m.users[ip] {
+		if !at.Before(since) {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryIPStore) CountIPTransactions(ctx context.Context, ip string, since time.Time) (int64, error) {
+	var n int64
+	for _, at := range m.txs[ip] {
+		if !at.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+type failingIPStore struct {
+	*memoryIPStore
+}
+
+func (failingIPStore) GetIPUsers(ctx context.Context, ip string, since time.Time) ([]string, error) {
+	return nil, errors.New("redis: connection refused")
+}
+
+func TestIPIntel_SharedIPAcrossAccounts(t *testing.T) {
+	store := newMemoryIPStore()
+	d := NewIPIntelDetector(stubReputation{}, store, DefaultIPIntelConfig())
+	now := time.Now()
+
+	for i := 0; i < 6; i++ {
+		tx := domain.Transaction{ID: fmt.Sprintf("tx-%d", i), UserID: fmt.Sprintf("user-%d", i), IP: "45.10.20.30", Timestamp: now}
+		if err := d.Record(context.Background(), tx, domain.DecisionAllow); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	facts := Facts{}
+	tx := domain.Transaction{ID: "tx-x", UserID: "user-new", IP: "45.10.20.30", Timestamp: now}
+	findings, err := d.Evaluate(context.Background(), tx, domain.User{}, facts)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(findings) != 1 || findings[0].Code != "IP_SHARED" {
+		t.Fatalf("Expected IP_SHARED finding, got: %+v", findings)
+	}
+	if facts["features.ip_users_window"] != 7 {
+		t.Errorf("Expected 7 distinct users on the IP, got: %v", facts["features.ip_users_window"])
+	}
+}
+
+func TestIPIntel_DenylistBlocksAndAllowlistBypasses(t *testing.T) {
+	rep := stubReputation{
+		"203.0.113.5": {Denied: true},
+		"10.1.2.3":    {Allowed: true, Proxy: true},
+	}
+	d := NewIPIntelDetector(rep, newMemoryIPStore(), DefaultIPIntelConfig())
+
+	findings, _ := d.Evaluate(context.Background(), domain.Transaction{UserID: "u", IP: "203.0.113.5"}, domain.User{}, Facts{})
+	if len(findings) != 1 || findings[0].Floor != domain.DecisionBlock {
+		t.Errorf("Expected deny list to force BLOCK, got: %+v", findings)
+	}
+
+	findings, _ = d.Evaluate(context.Background(), domain.Transaction{UserID: "u", IP: "10.1.2.3"}, domain.User{}, Facts{})
+	if len(findings) != 0 {
+		t.Errorf("Expected allow list to bypass proxy checks, got: %+v", findings)
+	}
+}
+
+func TestIPIntel_DenylistBlocksWhenVelocityStoreFails(t *testing.T) {
+	rep := stubReputation{"203.0.113.5": {Denied: true, Tor: true}}
+	ipIntel := NewIPIntelDetector(rep, failingIPStore{newMemoryIPStore()}, DefaultIPIntelConfig())
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(&mockAI{}, &mockRepo{}, &mockCache{}, publisher, WithDetectors(ipIntel))
+
+	tx := domain.Transaction{ID: "tx-1", UserID: "user-1", Amount: 10, IP: "203.0.113.5"}
+	if err := detector.Detect(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+	if got := publisher.PublishedAlert; got.Decision != domain.DecisionBlock {
+		t.Errorf("Expected the deny list to block despite the store outage, got %s", got.Decision)
+	}
+	if flags := publisher.PublishedAlert.IPFlags; len(flags) != 2 {
+		t.Errorf("Expected the reputation flags to be kept, got %v", flags)
+	}
+}
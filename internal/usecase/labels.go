@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type LabelRepository interface {
+	// LabelFraudEvent stores the label on the transaction's event and
+	// reports whether it changed. A nil event means the transaction is
+	// unknown.
+	LabelFraudEvent(ctx context.Context, l domain.FraudLabel) (*domain.FraudEvent, bool, error)
+	LabelMetrics(ctx context.Context, since time.Time) (*domain.LabelMetrics, error)
+}
+
+// LabelService attaches chargebacks, disputes and other late ground truth
+// to the decisions they concern and measures how good those decisions were.
+type LabelService struct {
+	repo    LabelRepository
+	labeler RiskLabeler
+	now     func() time.Time
+}
+
+// NewLabelService builds the service; labeler may be nil to leave user risk
+// untouched.
+func NewLabelService(repo LabelRepository, labeler RiskLabeler) *LabelService {
+	return &LabelService{repo: repo, labeler: labeler, now: time.Now}
+}
+
+// Apply labels the transaction's event and, when the label is new or
+// changed, moves the user's risk accordingly. It reports whether anything
+// changed, so redelivered labels are not counted twice.
+func (s *LabelService) Apply(ctx context.Context, l domain.FraudLabel) (bool, error) {
+	l.TransactionID = strings.TrimSpace(l.TransactionID)
+	if l.TransactionID == "" {
+		return false, fmt.Errorf("%w: transaction id is required", domain.ErrInvalidInput)
+	}
+	if l.Label != domain.LabelFraud && l.Label != domain.LabelLegit {
+		return false, fmt.Errorf("%w: label must be fraud or legit, got %q", domain.ErrInvalidInput, l.Label)
+	}
+	if l.Source == "" {
+		l.Source = "unknown"
+	}
+	if l.ReportedAt.IsZero() {
+		l.ReportedAt = s.now()
+	}
+
+	event, changed, err := s.repo.LabelFraudEvent(ctx, l)
+	if err != nil {
+		return false, fmt.Errorf("label fraud event %s: %w", l.TransactionID, err)
+	}
+	if event == nil {
+		return false, fmt.Errorf("%w: transaction %s", domain.ErrNotFound, l.TransactionID)
+	}
+	if !changed {
+		return false, nil
+	}
+	slog.Info("Fraud event labelled", "tx_id", l.TransactionID, "user_id", event.UserID, "label", l.Label, "source", l.Source, "decision", event.Decision)
+
+	if s.labeler != nil {
+		if err := s.labeler.ApplyLabel(ctx, event.UserID, l.Label == domain.LabelFraud, "label:"+l.Source, l.TransactionID); err != nil {
+			return true, fmt.Errorf("update user risk from label %s: %w", l.TransactionID, err)
+		}
+	}
+	return true, nil
+}
+
+// Metrics computes precision and recall of the decisions taken within the
+// window that have been labelled so far.
+func (s *LabelService) Metrics(ctx context.Context, window time.Duration) (*domain.LabelMetrics, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("%w: window must be positive", domain.ErrInvalidInput)
+	}
+	m, err := s.repo.LabelMetrics(ctx, s.now().Add(-window))
+	if err != nil {
+		return nil, err
+	}
+	m.Window = window.String()
+	if flagged := m.TruePositives + m.FalsePositives; flagged > 0 {
+		m.Precision = float64(m.TruePositives) / float64(flagged)
+	}
+	if fraud := m.TruePositives + m.FalseNegatives; fraud > 0 {
+		m.Recall = float64(m.TruePositives) / float64(fraud)
+	}
+	return m, nil
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type memoryLabels struct {
+	events map[string]*domain.FraudEvent
+}
+
+func (m *memoryLabels) LabelFraudEvent(ctx context.Context, l domain.FraudLabel) (*domain.FraudEvent, bool, error) {
+	e, ok := m.events[l.TransactionID]
+	if !ok {
+		return nil, false, nil
+	}
+	if e.Label == l.Label {
+		return e, false, nil
+	}
+	e.Label, e.LabelSource, e.LabeledAt = l.Label, l.Source, &l.ReportedAt
+	return e, true, nil
+}
+
+func (m *memoryLabels) LabelMetrics(ctx context.Context, since time.Time) (*domain.LabelMetrics, error) {
+	var out domain.LabelMetrics
+	for _, e := range m.events {
+		if e.CreatedAt.Before(since) {
+			continue
+		}
+		out.Decisions++
+		flagged := e.Decision != domain.DecisionAllow
+		switch e.Label {
+		case domain.LabelFraud:
+			out.Labeled++
+			out.Fraud++
+			if flagged {
+				out.TruePositives++
+			} else {
+				out.FalseNegatives++
+			}
+		case domain.LabelLegit:
+			out.Labeled++
+			out.Legit++
+			if flagged {
+				out.FalsePositives++
+			} else {
+				out.TrueNegatives++
+			}
+		}
+	}
+	return &out, nil
+}
+
+func TestLabelService_ApplyUpdatesEventAndRiskOnce(t *testing.T) {
+	repo := &memoryLabels{events: map[string]*domain.FraudEvent{
+		"tx-1": {TransactionID: "tx-1", UserID: "user-1", Decision: domain.DecisionAllow},
+	}}
+	labeler := &caseLabeler{labels: map[string]bool{}}
+	s := NewLabelService(repo, labeler)
+	ctx := context.Background()
+
+	chargeback := domain.FraudLabel{TransactionID: "tx-1", Label: domain.LabelFraud, Source: "chargeback"}
+	changed, err := s.Apply(ctx, chargeback)
+	if err != nil || !changed {
+		t.Fatalf("Expected the label to be applied, got changed=%v err=%v", changed, err)
+	}
+	e := repo.events["tx-1"]
+	if e.Label != domain.LabelFraud || e.LabelSource != "chargeback" || e.LabeledAt == nil {
+		t.Errorf("Expected the event to carry the label, got %+v", e)
+	}
+	if fraud, ok := labeler.labels["tx-1"]; !ok || !fraud {
+		t.Errorf("Expected the user's risk to be labelled fraud, got %v", labeler.labels)
+	}
+
+	delete(labeler.labels, "tx-1")
+	if changed, err := s.Apply(ctx, chargeback); err != nil || changed {
+		t.Errorf("Expected a redelivered label to be a no-op, got changed=%v err=%v", changed, err)
+	}
+	if len(labeler.labels) != 0 {
+		t.Errorf("Expected a redelivered label not to move risk again, got %v", labeler.labels)
+	}
+
+	if _, err := s.Apply(ctx, domain.FraudLabel{TransactionID: "tx-404", Label: domain.LabelFraud}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected not found for an unknown transaction, got %v", err)
+	}
+	if _, err := s.Apply(ctx, domain.FraudLabel{TransactionID: "tx-1", Label: "maybe"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Expected an unknown label to be rejected, got %v", err)
+	}
+}
+
+func TestLabelService_MetricsPrecisionRecall(t *testing.T) {
+	now := time.Now()
+	event := func(decision domain.Decision, label domain.LabelOutcome, age time.Duration) *domain.FraudEvent {
+		return &domain.FraudEvent{Decision: decision, Label: label, CreatedAt: now.Add(-age)}
+	}
+	repo := &memoryLabels{events: map[string]*domain.FraudEvent{
+		"tp-block":  event(domain.DecisionBlock, domain.LabelFraud, time.Hour),
+		"tp-review": event(domain.DecisionReview, domain.LabelFraud, time.Hour),
+		"fp":        event(domain.DecisionBlock, domain.LabelLegit, time.Hour),
+		"fn":        event(domain.DecisionAllow, domain.LabelFraud, time.Hour),
+		"tn":        event(domain.DecisionAllow, domain.LabelLegit, time.Hour),
+		"unlabeled": event(domain.DecisionBlock, "", time.Hour),
+		"old":       event(domain.DecisionAllow, domain.LabelFraud, 48*time.Hour),
+	}}
+	s := NewLabelService(repo, nil)
+
+	m, err := s.Metrics(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Decisions != 6 || m.Labeled != 5 || m.TruePositives != 2 || m.FalsePositives != 1 || m.FalseNegatives != 1 {
+		t.Errorf("Unexpected counts: %+v", m)
+	}
+	if math.Abs(m.Precision-2.0/3) > 1e-9 || math.Abs(m.Recall-2.0/3) > 1e-9 {
+		t.Errorf("Expected precision and recall of 2/3, got %.3f and %.3f", m.Precision, m.Recall)
+	}
+
+	if _, err := s.Metrics(context.Background(), 0); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Expected a non-positive window to be rejected, got %v", err)
+	}
+}
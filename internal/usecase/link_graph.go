@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+const SourceRingRisk = "ring_risk"
+
+type LinkRepository interface {
+	RecordLinks(ctx context.Context, userID string, refs []domain.EntityRef, at time.Time) error
+	GetLinksByUsers(ctx context.Context, userIDs []string, since time.Time) ([]domain.EntityLink, error)
+	GetLinksByEntities(ctx context.Context, refs []domain.EntityRef, since time.Time, limit int) ([]domain.EntityLink, error)
+}
+
+type LinkGraphConfig struct {
+	Window        time.Duration
+	MaxDepth      int
+	MaxUsers      int
+	MinRingUsers  int
+	PointsPerUser float64
+}
+
+func DefaultLinkGraphConfig() LinkGraphConfig {
+	return LinkGraphConfig{
+		Window:        30 * 24 * time.Hour,
+		MaxDepth:      2,
+		MaxUsers:      100,
+		MinRingUsers:  3,
+		PointsPerUser: 15,
+	}
+}
+
+// LinkGraph is the bipartite user <-> entity graph. Users are joined into a
+// component through IPs and devices; merchants are too common to join users
+// on their own and only count as a shared attribute.
+type LinkGraph struct {
+	repo LinkRepository
+	cfg  LinkGraphConfig
+}
+
+func NewLinkGraph(repo LinkRepository, cfg LinkGraphConfig) *LinkGraph {
+	return &LinkGraph{repo: repo, cfg: cfg}
+}
+
+func (g *LinkGraph) Record(ctx context.Context, tx domain.Transaction) error {
+	return g.repo.RecordLinks(ctx, tx.UserID, txEntities(tx), txTime(tx))
+}
+
+func (g *LinkGraph) Links(ctx context.Context, userID string) (*domain.LinkReport, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, fmt.Errorf("%w: user id is required", domain.ErrInvalidInput)
+	}
+	return g.component(ctx, userID, nil, time.Now())
+}
+
+// component walks the graph breadth-first from userID, up to MaxDepth user
+// hops and MaxUsers linked users. extra entities (those of a transaction not
+// recorded yet) are treated as if already linked to the user.
+func (g *LinkGraph) component(ctx context.Context, userID string, extra []domain.EntityRef, now time.Time) (*domain.LinkReport, error) {
+	since := now.Add(-g.cfg.Window)
+	own, err := g.repo.GetLinksByUsers(ctx, []string{userID}, since)
+	if err != nil {
+		return nil, err
+	}
+	report := &domain.LinkReport{UserID: userID, Entities: own}
+
+	seen := make(map[domain.EntityRef]struct{})
+	var frontier []domain.EntityRef
+	visit := func(ref domain.EntityRef) {
+		if _, ok := seen[ref]; ok || !joinsUsers(ref.Type) {
+			return
+		}
+		seen[ref] = struct{}{}
+		frontier = append(frontier, ref)
+	}
+	for _, l := range own {
+		visit(l.Ref())
+	}
+	for _, ref := range extra {
+		visit(ref)
+	}
+
+	linked := make(map[string]int)
+	for depth := 1; depth <= g.cfg.MaxDepth && len(frontier) > 0; depth++ {
+		links, err := g.repo.GetLinksByEntities(ctx, frontier, since, g.cfg.MaxUsers*len(frontier))
+		if err != nil {
+			return nil, err
+		}
+
+		var next []string
+		for _, l := range links {
+			if l.UserID == userID {
+				continue
+			}
+			i, ok := linked[l.UserID]
+			if !ok {
+				if len(report.Linked) >= g.cfg.MaxUsers {
+					report.Truncated = true
+					continue
+				}
+				i = len(report.Linked)
+				linked[l.UserID] = i
+				report.Linked = append(report.Linked, domain.LinkedUser{UserID: l.UserID, Depth: depth})
+				next = append(next, l.UserID)
+			}
+			if report.Linked[i].Depth == depth {
+				report.Linked[i].Shared = append(report.Linked[i].Shared, l.Ref())
+			}
+		}
+
+		frontier = nil
+		if depth == g.cfg.MaxDepth || len(next) == 0 {
+			break
+		}
+		userLinks, err := g.repo.GetLinksByUsers(ctx, next, since)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range userLinks {
+			visit(l.Ref())
+		}
+	}
+	return report, nil
+}
+
+// RingDetector raises RING_RISK when the user is linked to MinRingUsers or
+// more other accounts, weighting those that also paid the same merchant.
+type RingDetector struct {
+	graph *LinkGraph
+}
+
+func NewRingDetector(graph *LinkGraph) *RingDetector {
+	return &RingDetector{graph: graph}
+}
+
+func (d *RingDetector) Name() string {
+	return SourceRingRisk
+}
+
+func (d *RingDetector) Evaluate(ctx context.Context, tx domain.Transaction, _ domain.User, facts Facts) ([]Finding, error) {
+	now := txTime(tx)
+	report, err := d.graph.component(ctx, tx.UserID, txEntities(tx), now)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := make(map[domain.EntityRef]struct{})
+	var ids []string
+	for _, u := range report.Linked {
+		ids = append(ids, u.UserID)
+		if u.Depth != 1 {
+			continue
+		}
+		for _, ref := range u.Shared {
+			shared[ref] = struct{}{}
+		}
+	}
+	sharedIPs, sharedDevices := 0, 0
+	for ref := range shared {
+		switch ref.Type {
+		case domain.EntityIP:
+			sharedIPs++
+		case domain.EntityDevice:
+			sharedDevices++
+		}
+	}
+
+	merchantUsers := 0
+	if tx.Merchant != "" && len(ids) > 0 {
+		links, err := d.graph.repo.GetLinksByUsers(ctx, ids, now.Add(-d.graph.cfg.Window))
+		if err != nil {
+			return nil, err
+		}
+		paid := make(map[string]struct{})
+		for _, l := range links {
+			if l.EntityType == domain.EntityMerchant && l.EntityValue == tx.Merchant {
+				paid[l.UserID] = struct{}{}
+			}
+		}
+		merchantUsers = len(paid)
+	}
+
+	facts["features.ring_users"] = len(report.Linked)
+	facts["features.ring_shared_ips"] = sharedIPs
+	facts["features.ring_shared_devices"] = sharedDevices
+	facts["features.ring_merchant_users"] = merchantUsers
+
+	if len(report.Linked) < d.graph.cfg.MinRingUsers {
+		return nil, nil
+	}
+	return []Finding{{
+		Detector: SourceRingRisk,
+		Code:     "RING_RISK",
+		Score:    clamp(float64(len(report.Linked)+merchantUsers)*d.graph.cfg.PointsPerUser, 0, 100),
+		Message: fmt.Sprintf("Linked to %d accounts via %d shared IPs and %d shared devices, %d of them also paid %q",
+			len(report.Linked), sharedIPs, sharedDevices, merchantUsers, tx.Merchant),
+	}}, nil
+}
+
+func (d *RingDetector) Record(ctx context.Context, tx domain.Transaction, _ domain.Decision) error {
+	return d.graph.Record(ctx, tx)
+}
+
+func joinsUsers(t domain.EntityType) bool {
+	return slices.Contains([]domain.EntityType{domain.EntityIP, domain.EntityDevice}, t)
+}
+
+func txEntities(tx domain.Transaction) []domain.EntityRef {
+	var refs []domain.EntityRef
+	if tx.IP != "" {
+		refs = append(refs, domain.EntityRef{Type: domain.EntityIP, Value: tx.IP})
+	}
+	if tx.DeviceID != "" {
+		refs = append(refs, domain.EntityRef{Type: domain.EntityDevice, Value: tx.DeviceID})
+	}
+	if tx.Merchant != "" {
+		refs = append(refs, domain.EntityRef{Type: domain.EntityMerchant, Value: tx.Merchant})
+	}
+	return refs
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type memoryLinkRepo struct {
+	links []domain.EntityLink
+}
+
+func (m *memoryLinkRepo) RecordLinks(ctx context.Context, userID string, refs []domain.EntityRef, at time.Time) error {
+	for _, ref := range refs {
+		m.links = append(m.links, domain.EntityLink{
+			UserID: userID, EntityType: ref.Type, EntityValue: ref.Value, TxCount: 1, FirstSeen: at, LastSeen: at,
+		})
+	}
+	return nil
+}
+
+func (m *memoryLinkRepo) GetLinksByUsers(ctx context.Context, userIDs []string, since time.Time) ([]domain.EntityLink, error) {
+	var out []domain.EntityLink
+	for _, l := range m.links {
+		for _, id := range userIDs {
+			if l.UserID == id && !l.LastSeen.Before(since) {
+				out = append(out, l)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryLinkRepo) GetLinksByEntities(ctx context.Context, refs []domain.EntityRef, since time.Time, limit int) ([]domain.EntityLink, error) {
+	var out []domain.EntityLink
+	for _, l := range m.links {
+		for _, ref := range refs {
+			if l.Ref() == ref && !l.LastSeen.Before(since) && len(out) < limit {
+				out = append(out, l)
+			}
+		}
+	}
+	return out, nil
+}
+
+func TestRingDetector_SharedIPAcrossAccounts(t *testing.T) {
+	now := time.Now()
+	repo := &memoryLinkRepo{}
+	ip := domain.EntityRef{Type: domain.EntityIP, Value: "203.0.113.7"}
+	merchant := domain.EntityRef{Type: domain.EntityMerchant, Value: "Binance P2P Exchange"}
+	_ = repo.RecordLinks(context.Background(), "mule-1", []domain.EntityRef{ip, merchant}, now.Add(-time.Hour))
+	_ = repo.RecordLinks(context.Background(), "mule-2", []domain.EntityRef{ip, merchant}, now.Add(-time.Hour))
+	_ = repo.RecordLinks(context.Background(), "mule-3", []domain.EntityRef{ip, {Type: domain.EntityDevice, Value: "emu-1"}}, now.Add(-time.Hour))
+	_ = repo.RecordLinks(context.Background(), "mule-4", []domain.EntityRef{{Type: domain.EntityDevice, Value: "emu-1"}}, now.Add(-time.Hour))
+	_ = repo.RecordLinks(context.Background(), "stale", []domain.EntityRef{ip}, now.Add(-60*24*time.Hour))
+
+	d := NewRingDetector(NewLinkGraph(repo, DefaultLinkGraphConfig()))
+	tx := domain.Transaction{ID: "tx-1", UserID: "user-2", IP: ip.Value, Merchant: merchant.Value, Timestamp: now}
+
+	facts := Facts{}
+	findings, err := d.Evaluate(context.Background(), tx, domain.User{}, facts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if facts["features.ring_users"] != 4 {
+		t.Errorf("Expected 4 linked users (3 via IP, 1 via device), got %v", facts["features.ring_users"])
+	}
+	if facts["features.ring_merchant_users"] != 2 {
+		t.Errorf("Expected 2 linked users at the same merchant, got %v", facts["features.ring_merchant_users"])
+	}
+	if len(findings) != 1 || findings[0].Code != "RING_RISK" || findings[0].Score != 90 {
+		t.Errorf("Expected RING_RISK with score 90, got %+v", findings)
+	}
+}
+
+func TestLinkGraph_MerchantsDoNotJoinUsers(t *testing.T) {
+	repo := &memoryLinkRepo{}
+	graph := NewLinkGraph(repo, DefaultLinkGraphConfig())
+	for _, user := range []string{"user-1", "user-2", "user-3", "user-4"} {
+		tx := domain.Transaction{UserID: user, Merchant: "Local Supermarket", Timestamp: time.Now()}
+		if err := graph.Record(context.Background(), tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := graph.Links(context.Background(), "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Entities) != 1 || len(report.Linked) != 0 {
+		t.Errorf("Expected only the user's own merchant link, got %+v", report)
+	}
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+const SourceMerchant = "merchant"
+
+type MerchantRepository interface {
+	GetMerchant(ctx context.Context, name string) (*domain.Merchant, error)
+	ListMerchants(ctx context.Context) ([]domain.Merchant, error)
+	UpsertMerchant(ctx context.Context, m *domain.Merchant) error
+	DeleteMerchant(ctx context.Context, name string) error
+}
+
+type MerchantService struct {
+	repo  MerchantRepository
+	cache *RiskCache
+}
+
+// NewMerchantService creates the service; cache may be nil. When set, cached
+// AI verdicts for a merchant are dropped whenever its entry changes.
+func NewMerchantService(repo MerchantRepository, cache *RiskCache) *MerchantService {
+	return &MerchantService{repo: repo, cache: cache}
+}
+
+func (s *MerchantService) Get(ctx context.Context, name string) (*domain.Merchant, error) {
+	m, err := s.repo.GetMerchant(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, fmt.Errorf("merchant %q: %w", name, domain.ErrNotFound)
+	}
+	return m, nil
+}
+
+func (s *MerchantService) List(ctx context.Context) ([]domain.Merchant, error) {
+	return s.repo.ListMerchants(ctx)
+}
+
+func (s *MerchantService) Upsert(ctx context.Context, m *domain.Merchant) error {
+	m.Name = strings.TrimSpace(m.Name)
+	if m.Name == "" {
+		return fmt.Errorf("%w: merchant name is required", domain.ErrInvalidInput)
+	}
+	if m.RiskTier == "" {
+		m.RiskTier = domain.MerchantRiskMedium
+	}
+	if !m.RiskTier.Valid() {
+		return fmt.Errorf("%w: unknown risk tier %q", domain.ErrInvalidInput, m.RiskTier)
+	}
+	if m.MCC != "" && !validMCC(m.MCC) {
+		return fmt.Errorf("%w: mcc must have 4 digits", domain.ErrInvalidInput)
+	}
+	if err := s.repo.UpsertMerchant(ctx, m); err != nil {
+		return err
+	}
+	s.invalidate(ctx, m.Name)
+	return nil
+}
+
+func validMCC(mcc string) bool {
+	if len(mcc) != 4 {
+		return false
+	}
+	for _, c := range mcc {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *MerchantService) Delete(ctx context.Context, name string) error {
+	if err := s.repo.DeleteMerchant(ctx, name); err != nil {
+		return err
+	}
+	s.invalidate(ctx, name)
+	return nil
+}
+
+func (s *MerchantService) invalidate(ctx context.Context, name string) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.InvalidateMerchant(ctx, name); err != nil {
+		slog.Warn("Failed to invalidate risk cache", "merchant", name, "err", err)
+	}
+}
+
+// MerchantDetector publishes the registry entry for tx.Merchant as
+// "merchant.*" facts and blocks merchants flagged in the registry. The
+// risk tier is also used by the scorer to pick decision thresholds.
+type MerchantDetector struct {
+	repo MerchantRepository
+}
+
+func NewMerchantDetector(repo MerchantRepository) *MerchantDetector {
+	return &MerchantDetector{repo: repo}
+}
+
+func (d *MerchantDetector) Name() string {
+	return SourceMerchant
+}
+
+func (d *MerchantDetector) Evaluate(ctx context.Context, tx domain.Transaction, _ domain.User, facts Facts) ([]Finding, error) {
+	m, err := d.repo.GetMerchant(ctx, tx.Merchant)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		facts["merchant.registered"] = false
+		return nil, nil
+	}
+
+	facts["merchant.registered"] = true
+	facts["merchant.category"] = m.Category
+	facts["merchant.mcc"] = m.MCC
+	facts["merchant.risk_tier"] = string(m.RiskTier)
+	facts["merchant.country"] = m.Country
+	facts["merchant.blocked"] = m.IsBlocked
+
+	if !m.IsBlocked {
+		return nil, nil
+	}
+	return []Finding{{
+		Detector: SourceMerchant,
+		Code:     "MERCHANT_BLOCKED",
+		Score:    100,
+		Floor:    domain.DecisionBlock,
+		Message:  fmt.Sprintf("Merchant %q is blocked in the registry", m.Name),
+	}}, nil
+}
+
+func merchantTier(facts Facts) domain.MerchantRiskTier {
+	tier, _ := facts["merchant.risk_tier"].(string)
+	return domain.MerchantRiskTier(tier)
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type mockMerchantRepo struct {
+	merchants map[string]domain.Merchant
+}
+
+func (m *mockMerchantRepo) GetMerchant(ctx context.Context, name string) (*domain.Merchant, error) {
+	merchant, ok := m.merchants[name]
+	if !ok {
+		return nil, nil
+	}
+	return &merchant, nil
+}
+
+func (m *mockMerchantRepo) ListMerchants(ctx context.Context) ([]domain.Merchant, error) {
+	return nil, nil
+}
+
+func (m *mockMerchantRepo) UpsertMerchant(ctx context.Context, merchant *domain.Merchant) error {
+	m.merchants[merchant.Name] = *merchant
+	return nil
+}
+
+func (m *mockMerchantRepo) DeleteMerchant(ctx context.Context, name string) error {
+	delete(m.merchants, name)
+	return nil
+}
+
+func TestMerchantRegistry_HighRiskTierUsesStricterThresholds(t *testing.T) {
+	registry := &mockMerchantRepo{merchants: map[string]domain.Merchant{
+		"Binance P2P Exchange": {Name: "Binance P2P Exchange", Category: "Crypto Exchange", RiskTier: domain.MerchantRiskHigh},
+	}}
+	engine, err := NewRuleEngine([]Rule{{
+		ID:         "AMT-001",
+		Name:       "Large Amount",
+		Message:    "Amount exceeds 1000",
+		Conditions: []Condition{{Field: "tx.amount", Op: OpGt, Value: 1000}},
+		Action:     RuleActionScore,
+		Score:      30,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decide := func(merchant string) domain.Decision {
+		publisher := &mockPublisher{}
+		detector := NewFraudDetector(&mockAI{}, &mockRepo{}, &mockCache{}, publisher,
+			WithRuleEngine(engine),
+			WithDetectors(NewMerchantDetector(registry)),
+		)
+		if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-1", UserID: "user-2", Amount: 2000, Merchant: merchant}); err != nil {
+			t.Fatal(err)
+		}
+		return publisher.PublishedAlert.Decision
+	}
+
+	if got := decide("Local Bakery"); got != domain.DecisionAllow {
+		t.Errorf("Expected ALLOW for unregistered merchant at score 30, got %s", got)
+	}
+	if got := decide("Binance P2P Exchange"); got != domain.DecisionReview {
+		t.Errorf("Expected REVIEW for high-risk merchant at score 30, got %s", got)
+	}
+}
+
+func TestMerchantRegistry_BlockedMerchant(t *testing.T) {
+	d := NewMerchantDetector(&mockMerchantRepo{merchants: map[string]domain.Merchant{
+		"Unknown Global Store": {Name: "Unknown Global Store", RiskTier: domain.MerchantRiskHigh, IsBlocked: true},
+	}})
+
+	facts := Facts{}
+	findings, err := d.Evaluate(context.Background(), domain.Transaction{Merchant: "Unknown Global Store"}, domain.User{}, facts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Floor != domain.DecisionBlock {
+		t.Errorf("Expected MERCHANT_BLOCKED finding, got: %+v", findings)
+	}
+	if facts["merchant.risk_tier"] != "high" {
+		t.Errorf("Expected merchant facts to be published, got: %+v", facts)
+	}
+}
+
+func TestMerchantService_ValidatesTier(t *testing.T) {
+	svc := NewMerchantService(&mockMerchantRepo{merchants: map[string]domain.Merchant{}}, nil)
+	if err := svc.Upsert(context.Background(), &domain.Merchant{Name: "Shop", RiskTier: "extreme"}); err == nil {
+		t.Error("Expected error for unknown risk tier")
+	}
+}
+
+func TestMerchantService_ValidatesMCC(t *testing.T) {
+	svc := NewMerchantService(&mockMerchantRepo{merchants: map[string]domain.Merchant{}}, nil)
+	for _, mcc := range []string{"ab12", "12a4", "123", "12345"} {
+		if err := svc.Upsert(context.Background(), &domain.Merchant{Name: "Shop", MCC: mcc}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("Expected mcc %q to be rejected, got %v", mcc, err)
+		}
+	}
+	if err := svc.Upsert(context.Background(), &domain.Merchant{Name: "Shop", MCC: "5411"}); err != nil {
+		t.Errorf("Expected a 4-digit mcc to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type HistoryStore interface {
+	// FirstSeen returns when the user and each of refs were first seen. A
+	// zero time means the user has no history; unseen refs are absent.
+	FirstSeen(ctx context.Context, userID string, refs []domain.EntityRef) (time.Time, map[domain.EntityRef]time.Time, error)
+	// MarkSeen records the user and refs as seen at `at`, keeping earlier
+	// first-seen times.
+	MarkSeen(ctx context.Context, userID string, refs []domain.EntityRef, at time.Time) error
+}
+
+// EntityHistory remembers every merchant, location, country and IP a user
+// has paid with, so first-time use can be flagged.
+type EntityHistory struct {
+	store    HistoryStore
+	resolver GeoResolver
+}
+
+// NewEntityHistory creates the history. The resolver, when set, supplies the
+// country of known cities; otherwise the part after the last comma of the
+// location is used.
+func NewEntityHistory(store HistoryStore, resolver GeoResolver) *EntityHistory {
+	return &EntityHistory{store: store, resolver: resolver}
+}
+
+func (h *EntityHistory) Novelty(ctx context.Context, tx domain.Transaction) (domain.Novelty, error) {
+	refs := h.refs(tx)
+	userSeen, seen, err := h.store.FirstSeen(ctx, tx.UserID, refs)
+	if err != nil {
+		return domain.Novelty{}, err
+	}
+
+	var n domain.Novelty
+	if !userSeen.IsZero() {
+		n.DaysSinceFirstSeen = int(txTime(tx).Sub(userSeen).Hours() / 24)
+	}
+	for _, ref := range refs {
+		if _, ok := seen[ref]; ok {
+			continue
+		}
+		switch ref.Type {
+		case domain.EntityMerchant:
+			n.NewMerchant = true
+		case domain.EntityLocation:
+			n.NewLocation = true
+		case domain.EntityCountry:
+			n.NewCountry = true
+		case domain.EntityIP:
+			n.NewIP = true
+		}
+	}
+	return n, nil
+}
+
+func (h *EntityHistory) Record(ctx context.Context, tx domain.Transaction) error {
+	return h.store.MarkSeen(ctx, tx.UserID, h.refs(tx), txTime(tx))
+}
+
+func (h *EntityHistory) refs(tx domain.Transaction) []domain.EntityRef {
+	var refs []domain.EntityRef
+	if tx.Merchant != "" {
+		refs = append(refs, domain.EntityRef{Type: domain.EntityMerchant, Value: tx.Merchant})
+	}
+	if tx.Location != "" {
+		refs = append(refs, domain.EntityRef{Type: domain.EntityLocation, Value: tx.Location})
+		if country := h.country(tx.Location); country != "" {
+			refs = append(refs, domain.EntityRef{Type: domain.EntityCountry, Value: country})
+		}
+	}
+	if tx.IP != "" {
+		refs = append(refs, domain.EntityRef{Type: domain.EntityIP, Value: tx.IP})
+	}
+	return refs
+}
+
+func (h *EntityHistory) country(location string) string {
+	if h.resolver != nil {
+		if p, ok := h.resolver.Resolve(location); ok && p.Country != "" {
+			return strings.ToLower(p.Country)
+		}
+	}
+	if i := strings.LastIndex(location, ","); i >= 0 {
+		return strings.ToLower(strings.TrimSpace(location[i+1:]))
+	}
+	return ""
+}
+
+func noveltyFeatures(n domain.Novelty, facts Facts) {
+	facts["features.is_new_merchant"] = n.NewMerchant
+	facts["features.is_new_location"] = n.NewLocation
+	facts["features.is_new_country"] = n.NewCountry
+	facts["features.is_new_ip"] = n.NewIP
+	facts["features.days_since_first_seen"] = n.DaysSinceFirstSeen
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type memoryHistory struct {
+	user time.Time
+	seen map[domain.EntityRef]time.Time
+}
+
+func (m *memoryHistory) FirstSeen(ctx context.Context, userID string, refs []domain.EntityRef) (time.Time, map[domain.EntityRef]time.Time, error) {
+	return m.user, m.seen, nil
+}
+
+func (m *memoryHistory) MarkSeen(ctx context.Context, userID string, refs []domain.EntityRef, at time.Time) error {
+	if m.user.IsZero() {
+		m.user = at
+	}
+	for _, ref := range refs {
+		if _, ok := m.seen[ref]; !ok {
+			m.seen[ref] = at
+		}
+	}
+	return nil
+}
+
+type userCapturingAI struct {
+	mockAI
+	user domain.User
+}
+
+func (m *userCapturingAI) Analyze(ctx context.Context, tx domain.Transaction, user domain.User) (domain.FraudAlert, error) {
+	m.user = user
+	return m.mockAI.Analyze(ctx, tx, user)
+}
+
+func TestEntityHistory_FlagsFirstTimeCountry(t *testing.T) {
+	history := &memoryHistory{seen: make(map[domain.EntityRef]time.Time)}
+	ai := &userCapturingAI{}
+	repo := &mockRepo{}
+	detector := NewFraudDetector(ai, repo, &mockCache{}, &mockPublisher{},
+		WithEntityHistory(NewEntityHistory(history, nil)),
+	)
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	txs := []domain.Transaction{
+		{ID: "tx-1", UserID: "user-1", Amount: 40, Merchant: "Local Supermarket", Location: "Kyiv, Ukraine", IP: "93.72.10.1", Timestamp: start},
+		{ID: "tx-2", UserID: "user-1", Amount: 45, Merchant: "Local Supermarket", Location: "Lagos, Nigeria", IP: "93.72.10.1", Timestamp: start.Add(10 * 24 * time.Hour)},
+	}
+	for _, tx := range txs {
+		if err := detector.Detect(context.Background(), tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := domain.Novelty{NewLocation: true, NewCountry: true, DaysSinceFirstSeen: 10}
+	if ai.user.Novelty == nil || *ai.user.Novelty != want {
+		t.Fatalf("Expected AI to receive %+v, got %+v", want, ai.user.Novelty)
+	}
+	if _, ok := history.seen[domain.EntityRef{Type: domain.EntityCountry, Value: "nigeria"}]; !ok {
+		t.Errorf("Expected the new country to be recorded, got %v", history.seen)
+	}
+}
+
+func TestEntityHistory_NewUserHasEverythingNew(t *testing.T) {
+	h := NewEntityHistory(&memoryHistory{seen: make(map[domain.EntityRef]time.Time)}, nil)
+
+	n, err := h.Novelty(context.Background(), domain.Transaction{UserID: "user-9", Merchant: "Binance P2P Exchange", Location: "Singapore"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !n.NewMerchant || !n.NewLocation || n.NewCountry || n.NewIP || n.DaysSinceFirstSeen != 0 {
+		t.Errorf("Expected new merchant and location only, got %+v", n)
+	}
+}
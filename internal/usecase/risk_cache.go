@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type RiskCacheStore interface {
+	GetRiskCache(ctx context.Context, key domain.RiskCacheKey) (*domain.FraudAlert, error)
+	SetRiskCache(ctx context.Context, key domain.RiskCacheKey, alert domain.FraudAlert, ttl time.Duration) error
+	InvalidateUserRisk(ctx context.Context, userID string) error
+	InvalidateMerchantRisk(ctx context.Context, merchant string) error
+}
+
+// RiskCacheConfig bands amounts by the ascending AmountBands bounds and keeps
+// a verdict for the TTL of the decision it led to; a missing or zero TTL
+// leaves that decision uncached.
+type RiskCacheConfig struct {
+	AmountBands []float64
+	TTLs        map[domain.Decision]time.Duration
+}
+
+func DefaultRiskCacheConfig() RiskCacheConfig {
+	return RiskCacheConfig{
+		AmountBands: []float64{50, 200, 1000, 5000, 10000},
+		TTLs: map[domain.Decision]time.Duration{
+			domain.DecisionAllow:  5 * time.Minute,
+			domain.DecisionReview: time.Minute,
+			domain.DecisionBlock:  time.Minute,
+		},
+	}
+}
+
+// RiskCache reuses AI verdicts only within the context they were given in:
+// the same user, merchant, amount band and location. Analyst actions on a
+// user or merchant and every persisted change of the user's risk score
+// invalidate its verdicts, so a verdict never outlives the risk it was
+// given at.
+type RiskCache struct {
+	store RiskCacheStore
+	cfg   RiskCacheConfig
+}
+
+func NewRiskCache(store RiskCacheStore, cfg RiskCacheConfig) (*RiskCache, error) {
+	if !slices.IsSorted(cfg.AmountBands) || slices.ContainsFunc(cfg.AmountBands, func(b float64) bool { return b <= 0 }) {
+		return nil, fmt.Errorf("%w: amount bands must be positive and ascending", domain.ErrInvalidInput)
+	}
+	for decision, ttl := range cfg.TTLs {
+		if _, ok := domain.ParseDecision(string(decision)); !ok || ttl < 0 {
+			return nil, fmt.Errorf("%w: invalid risk cache ttl %s=%s", domain.ErrInvalidInput, decision, ttl)
+		}
+	}
+	return &RiskCache{store: store, cfg: cfg}, nil
+}
+
+func (c *RiskCache) Key(tx domain.Transaction) domain.RiskCacheKey {
+	return domain.RiskCacheKey{
+		UserID:     tx.UserID,
+		Merchant:   tx.Merchant,
+		AmountBand: sort.SearchFloat64s(c.cfg.AmountBands, tx.Amount),
+		Location:   strings.ToLower(strings.TrimSpace(tx.Location)),
+	}
+}
+
+func (c *RiskCache) Get(ctx context.Context, tx domain.Transaction) (*domain.FraudAlert, error) {
+	return c.store.GetRiskCache(ctx, c.Key(tx))
+}
+
+// Set caches the verdict for the TTL of the decision it contributed to.
+func (c *RiskCache) Set(ctx context.Context, tx domain.Transaction, alert domain.FraudAlert, decision domain.Decision) error {
+	ttl := c.cfg.TTLs[decision]
+	if ttl <= 0 {
+		return nil
+	}
+	return c.store.SetRiskCache(ctx, c.Key(tx), alert, ttl)
+}
+
+// InvalidateUser drops every verdict cached for the user.
+func (c *RiskCache) InvalidateUser(ctx context.Context, userID string) error {
+	if strings.TrimSpace(userID) == "" {
+		return fmt.Errorf("%w: user id is required", domain.ErrInvalidInput)
+	}
+	return c.store.InvalidateUserRisk(ctx, userID)
+}
+
+// InvalidateMerchant drops every verdict cached for the merchant.
+func (c *RiskCache) InvalidateMerchant(ctx context.Context, merchant string) error {
+	if strings.TrimSpace(merchant) == "" {
+		return fmt.Errorf("%w: merchant is required", domain.ErrInvalidInput)
+	}
+	return c.store.InvalidateMerchantRisk(ctx, merchant)
+}
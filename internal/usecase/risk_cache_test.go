@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type memoryRiskCache struct {
+	entries map[string]domain.FraudAlert
+	ttls    map[string]time.Duration
+	gens    map[string]int
+}
+
+func newMemoryRiskCache() *memoryRiskCache {
+	return &memoryRiskCache{
+		entries: map[string]domain.FraudAlert{},
+		ttls:    map[string]time.Duration{},
+		gens:    map[string]int{},
+	}
+}
+
+func (m *memoryRiskCache) key(k domain.RiskCacheKey) string {
+	return fmt.Sprintf("%+v/%d/%d", k, m.gens["user:"+k.UserID], m.gens["merchant:"+k.Merchant])
+}
+
+func (m *memoryRiskCache) GetRiskCache(ctx context.Context, key domain.RiskCacheKey) (*domain.FraudAlert, error) {
+	alert, ok := m.entries[m.key(key)]
+	if !ok {
+		return nil, nil
+	}
+	return &alert, nil
+}
+
+func (m *memoryRiskCache) SetRiskCache(ctx context.Context, key domain.RiskCacheKey, alert domain.FraudAlert, ttl time.Duration) error {
+	m.entries[m.key(key)] = alert
+	m.ttls[m.key(key)] = ttl
+	return nil
+}
+
+func (m *memoryRiskCache) InvalidateUserRisk(ctx context.Context, userID string) error {
+	m.gens["user:"+userID]++
+	return nil
+}
+
+func (m *memoryRiskCache) InvalidateMerchantRisk(ctx context.Context, merchant string) error {
+	m.gens["merchant:"+merchant]++
+	return nil
+}
+
+func TestFraudDetector_RiskCacheReusesVerdictOnlyInSameContext(t *testing.T) {
+	store := newMemoryRiskCache()
+	cache, err := NewRiskCache(store, DefaultRiskCacheConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ai := &mockAI{}
+	detector := NewFraudDetector(ai, &mockRepo{}, &mockCache{}, &mockPublisher{}, WithRiskCache(cache))
+	ctx := context.Background()
+
+	base := domain.Transaction{ID: "tx-1", UserID: "user-1", Amount: 40, Merchant: "Shop", Location: "Berlin"}
+	detect := func(tx domain.Transaction) {
+		t.Helper()
+		if err := detector.Detect(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	detect(base)
+	same := base
+	same.Amount, same.Location = 45, " berlin "
+	detect(same)
+	if ai.calls != 1 {
+		t.Fatalf("Expected a verdict to be reused within the same band and location, got %d AI calls", ai.calls)
+	}
+
+	large := base
+	large.Amount = 99999
+	detect(large)
+	elsewhere := base
+	elsewhere.Location = "Lagos"
+	detect(elsewhere)
+	if ai.calls != 3 {
+		t.Errorf("Expected another amount band or location to miss the cache, got %d AI calls", ai.calls)
+	}
+
+	if err := cache.InvalidateUser(ctx, "user-1"); err != nil {
+		t.Fatal(err)
+	}
+	detect(base)
+	if err := cache.InvalidateMerchant(ctx, "Shop"); err != nil {
+		t.Fatal(err)
+	}
+	detect(base)
+	if ai.calls != 5 {
+		t.Errorf("Expected invalidation to drop cached verdicts, got %d AI calls", ai.calls)
+	}
+}
+
+func TestFraudDetector_RiskUpdateInvalidatesCachedVerdicts(t *testing.T) {
+	cache, err := NewRiskCache(newMemoryRiskCache(), DefaultRiskCacheConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ai := &mockAI{}
+	recent := time.Now().Add(-time.Hour)
+	repo := &mockRepo{user: &domain.User{ID: "user-1", RiskScore: 10, RiskBaseline: 10, RiskUpdatedAt: &recent}}
+	riskRepo := &mockRiskRepo{user: repo.user}
+	risk := NewRiskUpdater(riskRepo, DefaultRiskPolicy(), cache)
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(ai, repo, &mockCache{}, publisher, WithRiskCache(cache), WithRiskUpdater(risk))
+	ctx := context.Background()
+
+	detect := func(tx domain.Transaction, want domain.Decision) {
+		t.Helper()
+		if err := detector.Detect(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+		if got := publisher.PublishedAlert.Decision; got != want {
+			t.Fatalf("Expected %s to be %s, got %s", tx.ID, want, got)
+		}
+	}
+	allow := domain.Transaction{ID: "tx-1", UserID: "user-1", Amount: 40, Merchant: "Shop", Location: "Berlin"}
+	block := domain.Transaction{ID: "tx-2", UserID: "user-1", Amount: 500, Merchant: "Casino", Location: "Lagos"}
+
+	detect(allow, domain.DecisionAllow)
+	detect(allow, domain.DecisionAllow)
+	if ai.calls != 1 {
+		t.Fatalf("Expected the allow to be reused while the risk score is unchanged, got %d AI calls", ai.calls)
+	}
+
+	ai.blocked = true
+	detect(block, domain.DecisionBlock)
+	ai.blocked = false
+	detect(allow, domain.DecisionAllow)
+	if ai.calls != 3 || len(riskRepo.changes) == 0 {
+		t.Errorf("Expected the block's risk update to drop the cached allow, got %d AI calls", ai.calls)
+	}
+
+	detect(block, domain.DecisionBlock)
+	if ai.calls != 3 {
+		t.Errorf("Expected the block cached after its own risk update to be reused, got %d AI calls", ai.calls)
+	}
+	ai.blocked = true
+	detect(block, domain.DecisionBlock)
+	if ai.calls != 4 {
+		t.Errorf("Expected the next risk update to drop the cached block, got %d AI calls", ai.calls)
+	}
+
+	if err := risk.ApplyLabel(ctx, "user-1", false, "analyst", "tx-2"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := cache.Get(ctx, block); got != nil {
+		t.Errorf("Expected an analyst label to drop the cached block, got %+v", got)
+	}
+}
+
+func TestRiskCache_TTLFollowsDecision(t *testing.T) {
+	store := newMemoryRiskCache()
+	cache, err := NewRiskCache(store, RiskCacheConfig{
+		AmountBands: []float64{100},
+		TTLs: map[domain.Decision]time.Duration{
+			domain.DecisionAllow:  5 * time.Minute,
+			domain.DecisionReview: time.Minute,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for decision, want := range map[domain.Decision]time.Duration{
+		domain.DecisionAllow:  5 * time.Minute,
+		domain.DecisionReview: time.Minute,
+	} {
+		tx := domain.Transaction{UserID: "user-1", Merchant: string(decision)}
+		if err := cache.Set(ctx, tx, domain.FraudAlert{}, decision); err != nil {
+			t.Fatal(err)
+		}
+		if got := store.ttls[store.key(cache.Key(tx))]; got != want {
+			t.Errorf("Expected %s verdicts to be kept for %s, got %s", decision, want, got)
+		}
+	}
+
+	blocked := domain.Transaction{UserID: "user-1", Merchant: "Casino"}
+	if err := cache.Set(ctx, blocked, domain.FraudAlert{}, domain.DecisionBlock); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := cache.Get(ctx, blocked); got != nil {
+		t.Errorf("Expected a decision without a TTL not to be cached, got %+v", got)
+	}
+}
+
+func TestNewRiskCache_Validates(t *testing.T) {
+	bad := []RiskCacheConfig{
+		{AmountBands: []float64{1000, 50}},
+		{AmountBands: []float64{0, 50}},
+		{TTLs: map[domain.Decision]time.Duration{"MAYBE": time.Minute}},
+		{TTLs: map[domain.Decision]time.Duration{domain.DecisionAllow: -time.Minute}},
+	}
+	for _, cfg := range bad {
+		if _, err := NewRiskCache(newMemoryRiskCache(), cfg); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("Expected %+v to be rejected, got %v", cfg, err)
+		}
+	}
+}
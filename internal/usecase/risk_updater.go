@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type RiskRepository interface {
+	GetUserByID(ctx context.Context, id string) (*domain.User, error)
+	UpdateUserRisk(ctx context.Context, userID string, score int, changes []domain.RiskScoreChange) error
+	GetRiskHistory(ctx context.Context, userID string, limit int) ([]domain.RiskScoreChange, error)
+	GetLastRiskChange(ctx context.Context, userID string, causes []domain.RiskCause) (*domain.RiskScoreChange, error)
+}
+
+type RiskPolicy struct {
+	BlockDelta    int
+	ReviewDelta   int
+	CleanDelta    int
+	CleanInterval time.Duration
+	QuietPeriod   time.Duration
+	FraudDelta    int
+	LegitDelta    int
+	HalfLife      time.Duration
+}
+
+func DefaultRiskPolicy() RiskPolicy {
+	return RiskPolicy{
+		BlockDelta:    10,
+		ReviewDelta:   4,
+		CleanDelta:    -2,
+		CleanInterval: 24 * time.Hour,
+		QuietPeriod:   7 * 24 * time.Hour,
+		FraudDelta:    30,
+		LegitDelta:    -15,
+		HalfLife:      30 * 24 * time.Hour,
+	}
+}
+
+// RiskUpdater keeps User.RiskScore in line with what actually happened to
+// the account. Scores drift back towards the user's baseline with the
+// configured half-life, and every persisted change is written to history.
+type RiskUpdater struct {
+	repo   RiskRepository
+	policy RiskPolicy
+	cache  *RiskCache
+	now    func() time.Time
+}
+
+// NewRiskUpdater creates the updater; cache may be nil. When set, analyst
+// labels and every persisted score change drop the user's cached AI
+// verdicts.
+func NewRiskUpdater(repo RiskRepository, policy RiskPolicy, cache *RiskCache) *RiskUpdater {
+	return &RiskUpdater{repo: repo, policy: policy, cache: cache, now: time.Now}
+}
+
+func (u *RiskUpdater) Effective(user domain.User) int {
+	if user.RiskUpdatedAt == nil || u.policy.HalfLife <= 0 {
+		return user.RiskScore
+	}
+	elapsed := u.now().Sub(*user.RiskUpdatedAt)
+	if elapsed <= 0 {
+		return user.RiskScore
+	}
+	factor := math.Pow(0.5, elapsed.Hours()/u.policy.HalfLife.Hours())
+	delta := float64(user.RiskScore-user.RiskBaseline) * factor
+	return clampScore(user.RiskBaseline + int(math.Round(delta)))
+}
+
+func (u *RiskUpdater) ApplyDecision(ctx context.Context, user domain.User, txID string, decision domain.Decision) error {
+	var delta int
+	var cause domain.RiskCause
+	switch decision {
+	case domain.DecisionBlock:
+		delta, cause = u.policy.BlockDelta, domain.RiskCauseBlock
+	case domain.DecisionReview:
+		delta, cause = u.policy.ReviewDelta, domain.RiskCauseReview
+	default:
+		if user.RiskUpdatedAt != nil && u.now().Sub(*user.RiskUpdatedAt) < u.policy.CleanInterval {
+			return nil
+		}
+		quiet, err := u.quietSinceAdverse(ctx, user.ID)
+		if err != nil || !quiet {
+			return err
+		}
+		delta, cause = u.policy.CleanDelta, domain.RiskCauseClean
+	}
+	changed, err := u.apply(ctx, user, delta, cause, "", txID)
+	if err != nil {
+		return err
+	}
+	if changed {
+		u.invalidate(ctx, user.ID)
+	}
+	return nil
+}
+
+// quietSinceAdverse reports whether the user's last BLOCK or REVIEW is at
+// least QuietPeriod old; clean credit is only granted after that.
+func (u *RiskUpdater) quietSinceAdverse(ctx context.Context, userID string) (bool, error) {
+	if u.policy.QuietPeriod <= 0 {
+		return true, nil
+	}
+	last, err := u.repo.GetLastRiskChange(ctx, userID, []domain.RiskCause{domain.RiskCauseBlock, domain.RiskCauseReview})
+	if err != nil {
+		return false, err
+	}
+	return last == nil || u.now().Sub(last.CreatedAt) >= u.policy.QuietPeriod, nil
+}
+
+func (u *RiskUpdater) ApplyLabel(ctx context.Context, userID string, fraud bool, actor, txID string) error {
+	user, err := u.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("user %s: %w", userID, domain.ErrNotFound)
+	}
+
+	delta, cause := u.policy.LegitDelta, domain.RiskCauseLegit
+	if fraud {
+		delta, cause = u.policy.FraudDelta, domain.RiskCauseFraud
+	}
+	if _, err := u.apply(ctx, *user, delta, cause, actor, txID); err != nil {
+		return err
+	}
+	u.invalidate(ctx, userID)
+	return nil
+}
+
+func (u *RiskUpdater) invalidate(ctx context.Context, userID string) {
+	if u.cache == nil {
+		return
+	}
+	if err := u.cache.InvalidateUser(ctx, userID); err != nil {
+		slog.Warn("Failed to invalidate risk cache", "user_id", userID, "err", err)
+	}
+}
+
+func (u *RiskUpdater) History(ctx context.Context, userID string, limit int) ([]domain.RiskScoreChange, error) {
+	return u.repo.GetRiskHistory(ctx, userID, limit)
+}
+
+// apply persists the decayed score plus delta and reports whether the stored
+// score changed.
+func (u *RiskUpdater) apply(ctx context.Context, user domain.User, delta int, cause domain.RiskCause, actor, txID string) (bool, error) {
+	var changes []domain.RiskScoreChange
+
+	current := u.Effective(user)
+	if current != user.RiskScore {
+		changes = append(changes, domain.RiskScoreChange{
+			UserID:   user.ID,
+			OldScore: user.RiskScore,
+			NewScore: current,
+			Cause:    domain.RiskCauseDecay,
+		})
+	}
+
+	// Adverse decisions are recorded even when the score is already capped,
+	// since the clean-credit quiet period is measured from them.
+	next := clampScore(current + delta)
+	if next != current || cause == domain.RiskCauseBlock || cause == domain.RiskCauseReview {
+		changes = append(changes, domain.RiskScoreChange{
+			UserID:        user.ID,
+			OldScore:      current,
+			NewScore:      next,
+			Cause:         cause,
+			Actor:         actor,
+			TransactionID: txID,
+		})
+	}
+
+	if err := u.repo.UpdateUserRisk(ctx, user.ID, next, changes); err != nil {
+		return false, err
+	}
+	if next == user.RiskScore {
+		return false, nil
+	}
+	slog.Debug("User risk updated", "user_id", user.ID, "old", user.RiskScore, "new", next, "cause", cause)
+	return true, nil
+}
+
+func clampScore(score int) int {
+	return max(0, min(100, score))
+}
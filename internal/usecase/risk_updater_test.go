@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type mockRiskRepo struct {
+	user    *domain.User
+	score   int
+	changes []domain.RiskScoreChange
+}
+
+func (m *mockRiskRepo) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	return m.user, nil
+}
+
+func (m *mockRiskRepo) UpdateUserRisk(ctx context.Context, userID string, score int, changes []domain.RiskScoreChange) error {
+	m.score = score
+	m.changes = append(m.changes, changes...)
+	return nil
+}
+
+func (m *mockRiskRepo) GetRiskHistory(ctx context.Context, userID string, limit int) ([]domain.RiskScoreChange, error) {
+	return m.changes, nil
+}
+
+func (m *mockRiskRepo) GetLastRiskChange(ctx context.Context, userID string, causes []domain.RiskCause) (*domain.RiskScoreChange, error) {
+	for i := len(m.changes) - 1; i >= 0; i-- {
+		if slices.Contains(causes, m.changes[i].Cause) {
+			return &m.changes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func TestRiskUpdater_DecaysTowardsBaseline(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	updated := now.Add(-30 * 24 * time.Hour)
+
+	u := NewRiskUpdater(&mockRiskRepo{}, DefaultRiskPolicy(), nil)
+	u.now = func() time.Time { return now }
+
+	user := domain.User{ID: "user-3", RiskScore: 95, RiskBaseline: 15, RiskUpdatedAt: &updated}
+	if got := u.Effective(user); got != 55 {
+		t.Errorf("Expected score halfway back to baseline (55) after one half-life, got %d", got)
+	}
+}
+
+func TestRiskUpdater_BlockRaisesScoreAndWritesHistory(t *testing.T) {
+	repo := &mockRiskRepo{}
+	u := NewRiskUpdater(repo, DefaultRiskPolicy(), nil)
+
+	user := domain.User{ID: "user-2", RiskScore: 85, RiskBaseline: 85}
+	if err := u.ApplyDecision(context.Background(), user, "tx-1", domain.DecisionBlock); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if repo.score != 95 {
+		t.Errorf("Expected score 95 after block, got %d", repo.score)
+	}
+	if len(repo.changes) != 1 || repo.changes[0].Cause != domain.RiskCauseBlock || repo.changes[0].TransactionID != "tx-1" {
+		t.Errorf("Expected one block history entry, got: %+v", repo.changes)
+	}
+}
+
+func TestRiskUpdater_CleanCreditIsThrottled(t *testing.T) {
+	repo := &mockRiskRepo{}
+	u := NewRiskUpdater(repo, DefaultRiskPolicy(), nil)
+
+	recent := time.Now().Add(-time.Hour)
+	user := domain.User{ID: "user-3", RiskScore: 40, RiskBaseline: 15, RiskUpdatedAt: &recent}
+	if err := u.ApplyDecision(context.Background(), user, "tx-2", domain.DecisionAllow); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(repo.changes) != 0 {
+		t.Errorf("Expected no clean credit within the clean interval, got: %+v", repo.changes)
+	}
+}
+
+func TestRiskUpdater_CleanCreditWaitsForQuietPeriod(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	updated := now.Add(-2 * 24 * time.Hour)
+	user := domain.User{ID: "user-4", RiskScore: 40, RiskBaseline: 40, RiskUpdatedAt: &updated}
+
+	for _, tc := range []struct {
+		blockedAgo time.Duration
+		credited   bool
+	}{
+		{2 * 24 * time.Hour, false},
+		{8 * 24 * time.Hour, true},
+	} {
+		repo := &mockRiskRepo{changes: []domain.RiskScoreChange{
+			{UserID: "user-4", Cause: domain.RiskCauseBlock, CreatedAt: now.Add(-tc.blockedAgo)},
+		}}
+		u := NewRiskUpdater(repo, DefaultRiskPolicy(), nil)
+		u.now = func() time.Time { return now }
+
+		if err := u.ApplyDecision(context.Background(), user, "tx-3", domain.DecisionAllow); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if credited := len(repo.changes) > 1; credited != tc.credited {
+			t.Errorf("Block %s ago: expected clean credit %v, got changes %+v", tc.blockedAgo, tc.credited, repo.changes)
+		}
+	}
+}
+
+func TestRiskUpdater_RecordsBlockAtCappedScore(t *testing.T) {
+	repo := &mockRiskRepo{}
+	u := NewRiskUpdater(repo, DefaultRiskPolicy(), nil)
+
+	user := domain.User{ID: "user-5", RiskScore: 100, RiskBaseline: 100}
+	if err := u.ApplyDecision(context.Background(), user, "tx-4", domain.DecisionBlock); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(repo.changes) != 1 || repo.changes[0].Cause != domain.RiskCauseBlock {
+		t.Errorf("Expected the block to be recorded at the capped score, got: %+v", repo.changes)
+	}
+}
@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type RuleAction string
+
+const (
+	RuleActionBlock  RuleAction = "block"
+	RuleActionReview RuleAction = "review"
+	RuleActionScore  RuleAction = "score"
+	RuleActionTag    RuleAction = "tag"
+)
+
+const (
+	OpEq       = "eq"
+	OpNe       = "ne"
+	OpGt       = "gt"
+	OpGte      = "gte"
+	OpLt       = "lt"
+	OpLte      = "lte"
+	OpIn       = "in"
+	OpNotIn    = "not_in"
+	OpContains = "contains"
+)
+
+// Facts is the flat view of a transaction that rules are evaluated against.
+// Keys are namespaced: "tx.*", "user.*", "merchant.*", "trust.*" and
+// "features.*".
+type Facts map[string]any
+
+type Condition struct {
+	Field string `json:"field" yaml:"field"`
+	Op    string `json:"op" yaml:"op"`
+	Value any    `json:"value" yaml:"value"`
+}
+
+type Rule struct {
+	ID         string      `json:"id" yaml:"id"`
+	Name       string      `json:"name" yaml:"name"`
+	Message    string      `json:"message" yaml:"message"`
+	Disabled   bool        `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	Conditions []Condition `json:"conditions" yaml:"conditions"`
+	Action     RuleAction  `json:"action" yaml:"action"`
+	Score      int         `json:"score,omitempty" yaml:"score,omitempty"`
+	Tag        string      `json:"tag,omitempty" yaml:"tag,omitempty"`
+}
+
+type RuleHit struct {
+	RuleID  string
+	Name    string
+	Message string
+	Action  RuleAction
+	Score   int
+	Tag     string
+}
+
+func (h RuleHit) String() string {
+	if h.Action == RuleActionScore {
+		return fmt.Sprintf("[%s] %s (rule %s, +%d)", h.Name, h.Message, h.RuleID, h.Score)
+	}
+	return fmt.Sprintf("[%s] %s (rule %s)", h.Name, h.Message, h.RuleID)
+}
+
+type RuleSet struct {
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+type RuleEngine struct {
+	rules []Rule
+}
+
+func NewRuleEngine(rules []Rule) (*RuleEngine, error) {
+	seen := make(map[string]struct{}, len(rules))
+	for _, r := range rules {
+		if r.ID == "" {
+			return nil, fmt.Errorf("rule without id")
+		}
+		if _, ok := seen[r.ID]; ok {
+			return nil, fmt.Errorf("duplicate rule id %q", r.ID)
+		}
+		seen[r.ID] = struct{}{}
+
+		switch r.Action {
+		case RuleActionBlock, RuleActionReview, RuleActionScore, RuleActionTag:
+		default:
+			return nil, fmt.Errorf("rule %s: unknown action %q", r.ID, r.Action)
+		}
+		if r.Action == RuleActionTag && r.Tag == "" {
+			return nil, fmt.Errorf("rule %s: tag action requires tag", r.ID)
+		}
+		if err := checkConditions(r.Conditions); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.ID, err)
+		}
+	}
+	return &RuleEngine{rules: rules}, nil
+}
+
+func checkConditions(conds []Condition) error {
+	if len(conds) == 0 {
+		return fmt.Errorf("no conditions")
+	}
+	for _, c := range conds {
+		switch c.Op {
+		case OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIn, OpNotIn, OpContains:
+		default:
+			return fmt.Errorf("unknown operator %q", c.Op)
+		}
+	}
+	return nil
+}
+
+func LoadRules(path string) ([]Rule, error) {
+	var set RuleSet
+	if err := decodeFile(path, &set); err != nil {
+		return nil, fmt.Errorf("rules file: %w", err)
+	}
+	return set.Rules, nil
+}
+
+// decodeFile reads YAML (.yaml, .yml) or JSON from path into v.
+func decodeFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, v)
+	default:
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	return nil
+}
+
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			ID:      "VEL-001",
+			Name:    "Velocity Block",
+			Message: "User exceeded transaction frequency limit",
+			Conditions: []Condition{
+				{Field: "features.velocity", Op: OpGt, Value: 10},
+			},
+			Action: RuleActionBlock,
+		},
+	}
+}
+
+func (e *RuleEngine) Rules() []Rule {
+	return e.rules
+}
+
+func (e *RuleEngine) Evaluate(facts Facts) []RuleHit {
+	var hits []RuleHit
+	for _, r := range e.rules {
+		if r.Disabled || !matchAll(r.Conditions, facts) {
+			continue
+		}
+		hits = append(hits, RuleHit{
+			RuleID:  r.ID,
+			Name:    r.Name,
+			Message: r.Message,
+			Action:  r.Action,
+			Score:   r.Score,
+			Tag:     r.Tag,
+		})
+	}
+	return hits
+}
+
+func matchAll(conds []Condition, facts Facts) bool {
+	for _, c := range conds {
+		actual, ok := facts[c.Field]
+		if !ok || !match(actual, c.Op, c.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func match(actual any, op string, expected any) bool {
+	switch op {
+	case OpEq:
+		return equal(actual, expected)
+	case OpNe:
+		return !equal(actual, expected)
+	case OpGt, OpGte, OpLt, OpLte:
+		a, okA := toFloat(actual)
+		b, okB := toFloat(expected)
+		if !okA || !okB {
+			return false
+		}
+		switch op {
+		case OpGt:
+			return a > b
+		case OpGte:
+			return a >= b
+		case OpLt:
+			return a < b
+		default:
+			return a <= b
+		}
+	case OpIn, OpNotIn:
+		list, ok := expected.([]any)
+		if !ok {
+			return false
+		}
+		found := false
+		for _, v := range list {
+			if equal(actual, v) {
+				found = true
+				break
+			}
+		}
+		return found == (op == OpIn)
+	case OpContains:
+		return strings.Contains(strings.ToLower(fmt.Sprint(actual)), strings.ToLower(fmt.Sprint(expected)))
+	}
+	return false
+}
+
+func equal(a, b any) bool {
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			return fa == fb
+		}
+	}
+	if ba, ok := a.(bool); ok {
+		bb, ok := b.(bool)
+		return ok && ba == bb
+	}
+	return strings.EqualFold(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
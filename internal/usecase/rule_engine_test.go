@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRuleEngine_EvaluateMatchesAllConditions(t *testing.T) {
+	engine, err := NewRuleEngine([]Rule{
+		{
+			ID:      "AMT-001",
+			Name:    "Large Amount",
+			Message: "Amount too large",
+			Conditions: []Condition{
+				{Field: "tx.amount", Op: OpGt, Value: 1000},
+				{Field: "tx.merchant", Op: OpContains, Value: "p2p"},
+			},
+			Action: RuleActionBlock,
+		},
+		{
+			ID:         "GEO-001",
+			Name:       "Risky Country",
+			Message:    "Location on watchlist",
+			Conditions: []Condition{{Field: "tx.location", Op: OpIn, Value: []any{"Lagos, Nigeria"}}},
+			Action:     RuleActionReview,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got: %v", err)
+	}
+
+	hits := engine.Evaluate(Facts{
+		"tx.amount":   5000.0,
+		"tx.merchant": "Binance P2P Exchange",
+		"tx.location": "Singapore",
+	})
+
+	if len(hits) != 1 || hits[0].RuleID != "AMT-001" {
+		t.Fatalf("Expected only AMT-001 to fire, got: %+v", hits)
+	}
+}
+
+func TestRuleEngine_RejectsInvalidRules(t *testing.T) {
+	cases := map[string]Rule{
+		"missing id":     {Action: RuleActionBlock, Conditions: []Condition{{Field: "tx.amount", Op: OpGt, Value: 1}}},
+		"unknown action": {ID: "R1", Action: "explode", Conditions: []Condition{{Field: "tx.amount", Op: OpGt, Value: 1}}},
+		"unknown op":     {ID: "R1", Action: RuleActionBlock, Conditions: []Condition{{Field: "tx.amount", Op: "approx", Value: 1}}},
+		"tag w/o tag":    {ID: "R1", Action: RuleActionTag, Conditions: []Condition{{Field: "tx.amount", Op: OpGt, Value: 1}}},
+	}
+
+	for name, rule := range cases {
+		if _, err := NewRuleEngine([]Rule{rule}); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestLoadRules_YAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	content := `
+rules:
+  - id: VEL-002
+    name: Burst
+    message: Too many transactions
+    conditions:
+      - { field: features.velocity, op: gte, value: 5 }
+    action: score
+    score: 25
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := LoadRules(path)
+	if err != nil {
+		t.Fatalf("Expected rules to load, got: %v", err)
+	}
+
+	engine, err := NewRuleEngine(rules)
+	if err != nil {
+		t.Fatalf("Expected valid rules, got: %v", err)
+	}
+
+	hits := engine.Evaluate(Facts{"features.velocity": 7})
+	if len(hits) != 1 || hits[0].Score != 25 {
+		t.Errorf("Expected VEL-002 to add 25 points, got: %+v", hits)
+	}
+}
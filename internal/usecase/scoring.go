@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+const (
+	SourceRules    = "rules"
+	SourceVelocity = "velocity"
+	SourceAI       = "ai"
+)
+
+type Thresholds struct {
+	Review int
+	Block  int
+}
+
+type ScoringConfig struct {
+	Weights         map[string]float64
+	VelocityLimit   int
+	ReviewThreshold int
+	BlockThreshold  int
+	TierThresholds  map[domain.MerchantRiskTier]Thresholds
+}
+
+func DefaultScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		Weights: map[string]float64{
+			SourceRules:    1.0,
+			SourceVelocity: 0.3,
+			SourceAI:       0.7,
+
+			SourceImpossibleTravel: 0.8,
+			SourceAmountAnomaly:    0.8,
+			SourceIPIntel:          0.8,
+			SourceRingRisk:         0.6,
+			SourceTrust:            -0.3,
+			SourceCardTesting:      0.8,
+			SourceStructuring:      0.6,
+		},
+		VelocityLimit:   10,
+		ReviewThreshold: 40,
+		BlockThreshold:  70,
+		TierThresholds: map[domain.MerchantRiskTier]Thresholds{
+			domain.MerchantRiskHigh: {Review: 25, Block: 55},
+		},
+	}
+}
+
+type Scorer struct {
+	cfg ScoringConfig
+}
+
+func NewScorer(cfg ScoringConfig) (*Scorer, error) {
+	if cfg.ReviewThreshold <= 0 || cfg.ReviewThreshold > cfg.BlockThreshold || cfg.BlockThreshold > 100 {
+		return nil, fmt.Errorf("invalid thresholds: review=%d block=%d", cfg.ReviewThreshold, cfg.BlockThreshold)
+	}
+	for tier, t := range cfg.TierThresholds {
+		if t.Review <= 0 || t.Review > t.Block || t.Block > 100 {
+			return nil, fmt.Errorf("invalid thresholds for %s merchants: review=%d block=%d", tier, t.Review, t.Block)
+		}
+	}
+	if cfg.VelocityLimit <= 0 {
+		return nil, fmt.Errorf("velocity limit must be positive")
+	}
+
+	if cfg.TierThresholds == nil {
+		cfg.TierThresholds = DefaultScoringConfig().TierThresholds
+	}
+
+	weights := DefaultScoringConfig().Weights
+	for source, w := range cfg.Weights {
+		weights[source] = w
+	}
+	cfg.Weights = weights
+
+	return &Scorer{cfg: cfg}, nil
+}
+
+// Score combines per-source signals, each on a 0-100 scale, into a single
+// weighted 0-100 risk score.
+func (s *Scorer) Score(signals map[string]float64) int {
+	var total float64
+	for source, value := range signals {
+		total += s.cfg.Weights[source] * clamp(value, 0, 100)
+	}
+	return int(math.Round(clamp(total, 0, 100)))
+}
+
+// Decide maps a score onto a decision. Merchants in a configured risk tier
+// use that tier's thresholds instead of the global ones.
+func (s *Scorer) Decide(score int, floor domain.Decision, tier domain.MerchantRiskTier) domain.Decision {
+	t := Thresholds{Review: s.cfg.ReviewThreshold, Block: s.cfg.BlockThreshold}
+	if override, ok := s.cfg.TierThresholds[tier]; ok {
+		t = override
+	}
+
+	decision := domain.DecisionAllow
+	switch {
+	case score >= t.Block:
+		decision = domain.DecisionBlock
+	case score >= t.Review:
+		decision = domain.DecisionReview
+	}
+	return domain.MaxDecision(decision, floor)
+}
+
+// Contribution is the number of points a single signal adds to the score.
+func (s *Scorer) Contribution(source string, value float64) int {
+	return int(math.Round(s.cfg.Weights[source] * clamp(value, 0, 100)))
+}
+
+func (s *Scorer) VelocitySignal(velocity int) float64 {
+	return clamp(float64(velocity)*100/float64(s.cfg.VelocityLimit), 0, 100)
+}
+
+func aiSignal(alert domain.FraudAlert) float64 {
+	if alert.RiskScore > 0 {
+		return float64(alert.RiskScore)
+	}
+	if alert.IsBlocked {
+		return 100
+	}
+	return 0
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
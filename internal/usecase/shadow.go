@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type ShadowRepository interface {
+	SaveShadowDecision(ctx context.Context, d *domain.ShadowDecision) error
+	ShadowReport(ctx context.Context, since time.Time) ([]domain.ShadowReport, error)
+}
+
+// Shadow is a candidate variant of the live logic. Nil Rules or Scorer fall
+// back to the live ones; Detectors run in addition to the live detectors.
+// Shadows see the same facts, findings and AI verdict as the live path but
+// their decisions are only logged, never published.
+type Shadow struct {
+	Name      string
+	Rules     *RuleEngine
+	Scorer    *Scorer
+	Detectors []Detector
+}
+
+func (d *FraudDetector) runShadows(ctx context.Context, tx domain.Transaction, user domain.User, facts Facts, findings []Finding, ai aiVerdict, velocity int, live outcome) {
+	if d.shadowLog == nil {
+		return
+	}
+	for _, s := range d.shadows {
+		rules, scorer := d.rules, d.scorer
+		if s.Rules != nil {
+			rules = s.Rules
+		}
+		if s.Scorer != nil {
+			scorer = s.Scorer
+		}
+
+		shadowFacts := maps.Clone(facts)
+		shadowFindings := slices.Clone(findings)
+		for _, det := range s.Detectors {
+			found, err := det.Evaluate(ctx, tx, user, shadowFacts)
+			if err != nil {
+				slog.Warn("Shadow detector failed", "shadow", s.Name, "detector", det.Name(), "tx_id", tx.ID, "err", err)
+				continue
+			}
+			shadowFindings = append(shadowFindings, found...)
+		}
+
+		verdict, score, decision := decide(rules, scorer, shadowFacts, shadowFindings, ai.alert, velocity)
+		reasons := verdict.explain(scorer, ai, velocity)
+		if ai.failed {
+			var fallback domain.Reason
+			decision, fallback = d.degraded.apply(shadowFacts, verdict, score, decision)
+			reasons = append(reasons, fallback)
+		}
+		err := d.shadowLog.SaveShadowDecision(ctx, &domain.ShadowDecision{
+			Shadow:         s.Name,
+			TransactionID:  tx.ID,
+			UserID:         tx.UserID,
+			LiveDecision:   live.decision,
+			LiveScore:      live.score,
+			ShadowDecision: decision,
+			ShadowScore:    score,
+			Reason:         reasons.Summary(decision),
+		})
+		if err != nil {
+			slog.Warn("Failed to save shadow decision", "shadow", s.Name, "tx_id", tx.ID, "err", err)
+		}
+
+		// Stateful shadow detectors learn from the live outcome, as their
+		// live counterparts would.
+		for _, det := range s.Detectors {
+			if rec, ok := det.(Recorder); ok {
+				if err := rec.Record(ctx, tx, live.decision); err != nil {
+					slog.Warn("Shadow detector record failed", "shadow", s.Name, "detector", det.Name(), "tx_id", tx.ID, "err", err)
+				}
+			}
+		}
+	}
+}
+
+type ShadowService struct {
+	repo ShadowRepository
+}
+
+func NewShadowService(repo ShadowRepository) *ShadowService {
+	return &ShadowService{repo: repo}
+}
+
+// Report compares live and shadow decisions logged within the window.
+func (s *ShadowService) Report(ctx context.Context, window time.Duration) ([]domain.ShadowReport, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("%w: window must be positive", domain.ErrInvalidInput)
+	}
+	reports, err := s.repo.ShadowReport(ctx, time.Now().Add(-window))
+	if err != nil {
+		return nil, err
+	}
+	for i := range reports {
+		if reports[i].Total > 0 {
+			reports[i].AgreementRate = float64(reports[i].Agreements) / float64(reports[i].Total)
+		}
+	}
+	return reports, nil
+}
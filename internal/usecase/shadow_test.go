@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type memoryShadowRepo struct {
+	decisions []*domain.ShadowDecision
+	reports   []domain.ShadowReport
+}
+
+func (m *memoryShadowRepo) SaveShadowDecision(ctx context.Context, d *domain.ShadowDecision) error {
+	m.decisions = append(m.decisions, d)
+	return nil
+}
+
+func (m *memoryShadowRepo) ShadowReport(ctx context.Context, since time.Time) ([]domain.ShadowReport, error) {
+	return m.reports, nil
+}
+
+func TestFraudDetector_ShadowDoesNotAffectPublishedAlert(t *testing.T) {
+	candidate, err := NewRuleEngine([]Rule{{
+		ID:         "AMT-900",
+		Name:       "Candidate Amount Block",
+		Message:    "Amount exceeds 10000",
+		Conditions: []Condition{{Field: "tx.amount", Op: OpGt, Value: 10000}},
+		Action:     RuleActionBlock,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	strict := DefaultScoringConfig()
+	strict.ReviewThreshold, strict.BlockThreshold = 5, 60
+	strictScorer, err := NewScorer(strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shadows := &memoryShadowRepo{}
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(&mockAI{}, &mockRepo{}, &mockCache{activity: recentActivity(3, "Kyiv, Ukraine")}, publisher,
+		WithShadows(shadows,
+			Shadow{Name: "candidate-rules", Rules: candidate},
+			Shadow{Name: "strict", Scorer: strictScorer},
+		),
+	)
+
+	if err := detector.Detect(context.Background(), domain.Transaction{ID: "tx-1", UserID: "user-1", Amount: 15000}); err != nil {
+		t.Fatal(err)
+	}
+
+	if publisher.PublishedAlert.Decision != domain.DecisionAllow {
+		t.Fatalf("Expected live decision ALLOW, got %s", publisher.PublishedAlert.Decision)
+	}
+	if len(shadows.decisions) != 2 {
+		t.Fatalf("Expected 2 shadow decisions, got %d", len(shadows.decisions))
+	}
+
+	want := map[string]domain.Decision{
+		"candidate-rules": domain.DecisionBlock,
+		"strict":          domain.DecisionReview,
+	}
+	for _, d := range shadows.decisions {
+		if d.ShadowDecision != want[d.Shadow] || d.LiveDecision != domain.DecisionAllow {
+			t.Errorf("%s: expected shadow %s vs live ALLOW, got %s vs %s", d.Shadow, want[d.Shadow], d.ShadowDecision, d.LiveDecision)
+		}
+	}
+}
+
+func TestShadowService_AgreementRate(t *testing.T) {
+	svc := NewShadowService(&memoryShadowRepo{reports: []domain.ShadowReport{
+		{Shadow: "strict", Total: 200, Agreements: 150},
+		{Shadow: "idle"},
+	}})
+
+	reports, err := svc.Report(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reports[0].AgreementRate != 0.75 || reports[1].AgreementRate != 0 {
+		t.Errorf("Unexpected agreement rates: %+v", reports)
+	}
+}
@@ -74,6 +74,7 @@ func (s *Simulator) generateTransaction() domain.Transaction {
 		Merchant:  merchant,
 		Location:  location,
 		IP:        fmt.Sprintf("192.168.1.%d", rand.Intn(254)+1),
+		DeviceID:  "device-" + userID,
 		Timestamp: time.Now(),
 	}
 }
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+const SourceTrust = "trust"
+
+type TrustRepository interface {
+	GetTrustedEntities(ctx context.Context, userID string) ([]domain.TrustedEntity, error)
+	UpsertTrustedEntity(ctx context.Context, e *domain.TrustedEntity) error
+	DeleteTrustedEntity(ctx context.Context, userID string, ref domain.EntityRef) error
+	// RecordCleanEntities counts a clean transaction against each entity and
+	// extends learned entries to expiresAt. Manual entries keep their expiry.
+	RecordCleanEntities(ctx context.Context, userID string, refs []domain.EntityRef, at, expiresAt time.Time) error
+	// ForgetLearnedEntities drops learned entries so they have to be earned
+	// again. Manual entries are kept.
+	ForgetLearnedEntities(ctx context.Context, userID string, refs []domain.EntityRef) error
+}
+
+type TrustConfig struct {
+	// MinClean is the number of clean transactions after which a learned
+	// entity is trusted.
+	MinClean int64
+	// TTL is how long a learned entity stays trusted after its last clean
+	// transaction.
+	TTL time.Duration
+}
+
+func DefaultTrustConfig() TrustConfig {
+	return TrustConfig{
+		MinClean: 5,
+		TTL:      90 * 24 * time.Hour,
+	}
+}
+
+type TrustService struct {
+	repo TrustRepository
+	cfg  TrustConfig
+}
+
+func NewTrustService(repo TrustRepository, cfg TrustConfig) *TrustService {
+	return &TrustService{repo: repo, cfg: cfg}
+}
+
+func (s *TrustService) List(ctx context.Context, userID string) ([]domain.TrustedEntity, error) {
+	entries, err := s.repo.GetTrustedEntities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range entries {
+		entries[i].Active = entries[i].Trusted(now, s.cfg.MinClean)
+	}
+	return entries, nil
+}
+
+// Trust adds a manual entry, or turns a learned one into a manual one.
+func (s *TrustService) Trust(ctx context.Context, e *domain.TrustedEntity) error {
+	e.EntityValue = strings.TrimSpace(e.EntityValue)
+	switch e.EntityType {
+	case domain.EntityMerchant, domain.EntityLocation, domain.EntityIP:
+	default:
+		return fmt.Errorf("%w: entity type must be merchant, location or ip", domain.ErrInvalidInput)
+	}
+	if e.UserID == "" || e.EntityValue == "" || e.CreatedBy == "" {
+		return fmt.Errorf("%w: user, entity value and actor are required", domain.ErrInvalidInput)
+	}
+	if e.ExpiresAt != nil && !e.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("%w: expiry must be in the future", domain.ErrInvalidInput)
+	}
+	e.Source = domain.TrustManual
+	if err := s.repo.UpsertTrustedEntity(ctx, e); err != nil {
+		return err
+	}
+	e.Active = true
+	return nil
+}
+
+func (s *TrustService) Untrust(ctx context.Context, userID string, ref domain.EntityRef) error {
+	return s.repo.DeleteTrustedEntity(ctx, userID, ref)
+}
+
+// TrustDetector lowers the score of transactions at a user's trusted
+// merchants, locations and IPs, and learns new ones from clean
+// transactions. It publishes "trust.*" facts; "trust.all" is set when every
+// entity of the transaction is trusted.
+type TrustDetector struct {
+	repo TrustRepository
+	cfg  TrustConfig
+}
+
+func NewTrustDetector(repo TrustRepository, cfg TrustConfig) *TrustDetector {
+	return &TrustDetector{repo: repo, cfg: cfg}
+}
+
+func (d *TrustDetector) Name() string {
+	return SourceTrust
+}
+
+func (d *TrustDetector) Evaluate(ctx context.Context, tx domain.Transaction, _ domain.User, facts Facts) ([]Finding, error) {
+	refs := trustRefs(tx)
+	facts["trust.all"] = false
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	entries, err := d.repo.GetTrustedEntities(ctx, tx.UserID)
+	if err != nil {
+		return nil, err
+	}
+	now := txTime(tx)
+	trusted := make(map[domain.EntityRef]bool, len(entries))
+	for _, e := range entries {
+		trusted[e.Ref()] = e.Trusted(now, d.cfg.MinClean)
+	}
+
+	var matched []string
+	for _, ref := range refs {
+		facts["trust."+string(ref.Type)] = trusted[ref]
+		if trusted[ref] {
+			matched = append(matched, string(ref.Type))
+		}
+	}
+	if len(matched) == 0 {
+		return nil, nil
+	}
+	facts["trust.all"] = len(matched) == len(refs)
+
+	return []Finding{{
+		Detector: SourceTrust,
+		Code:     "TRUSTED_ENTITY",
+		Score:    float64(len(matched)) * 100 / float64(len(refs)),
+		Floor:    domain.DecisionAllow,
+		Message:  "Trusted " + strings.Join(matched, ", ") + " for this user",
+	}}, nil
+}
+
+// Record counts allowed transactions towards learned trust. Anything that
+// was not allowed resets what was learned about its entities.
+func (d *TrustDetector) Record(ctx context.Context, tx domain.Transaction, decision domain.Decision) error {
+	refs := trustRefs(tx)
+	if len(refs) == 0 {
+		return nil
+	}
+	if decision != domain.DecisionAllow {
+		return d.repo.ForgetLearnedEntities(ctx, tx.UserID, refs)
+	}
+	at := txTime(tx)
+	return d.repo.RecordCleanEntities(ctx, tx.UserID, refs, at, at.Add(d.cfg.TTL))
+}
+
+func trustRefs(tx domain.Transaction) []domain.EntityRef {
+	var refs []domain.EntityRef
+	if tx.Merchant != "" {
+		refs = append(refs, domain.EntityRef{Type: domain.EntityMerchant, Value: tx.Merchant})
+	}
+	if tx.Location != "" {
+		refs = append(refs, domain.EntityRef{Type: domain.EntityLocation, Value: tx.Location})
+	}
+	if tx.IP != "" {
+		refs = append(refs, domain.EntityRef{Type: domain.EntityIP, Value: tx.IP})
+	}
+	return refs
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type memoryTrustRepo struct {
+	entries map[domain.EntityRef]domain.TrustedEntity
+}
+
+func newMemoryTrustRepo(manual ...domain.EntityRef) *memoryTrustRepo {
+	m := &memoryTrustRepo{entries: make(map[domain.EntityRef]domain.TrustedEntity)}
+	for _, ref := range manual {
+		m.entries[ref] = domain.TrustedEntity{EntityType: ref.Type, EntityValue: ref.Value, Source: domain.TrustManual}
+	}
+	return m
+}
+
+func (m *memoryTrustRepo) GetTrustedEntities(ctx context.Context, userID string) ([]domain.TrustedEntity, error) {
+	var out []domain.TrustedEntity
+	for _, e := range m.entries {
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (m *memoryTrustRepo) UpsertTrustedEntity(ctx context.Context, e *domain.TrustedEntity) error {
+	m.entries[e.Ref()] = *e
+	return nil
+}
+
+func (m *memoryTrustRepo) DeleteTrustedEntity(ctx context.Context, userID string, ref domain.EntityRef) error {
+	delete(m.entries, ref)
+	return nil
+}
+
+func (m *memoryTrustRepo) RecordCleanEntities(ctx context.Context, userID string, refs []domain.EntityRef, at, expiresAt time.Time) error {
+	for _, ref := range refs {
+		e, ok := m.entries[ref]
+		if !ok {
+			e = domain.TrustedEntity{EntityType: ref.Type, EntityValue: ref.Value, Source: domain.TrustLearned}
+		}
+		e.CleanCount++
+		if e.Source == domain.TrustLearned {
+			e.ExpiresAt = &expiresAt
+		}
+		m.entries[ref] = e
+	}
+	return nil
+}
+
+func (m *memoryTrustRepo) ForgetLearnedEntities(ctx context.Context, userID string, refs []domain.EntityRef) error {
+	for _, ref := range refs {
+		if m.entries[ref].Source == domain.TrustLearned {
+			delete(m.entries, ref)
+		}
+	}
+	return nil
+}
+
+func TestTrustDetector_LearnsAfterCleanTransactionsAndSkipsAI(t *testing.T) {
+	repo := newMemoryTrustRepo()
+	ai := &mockAI{}
+	publisher := &mockPublisher{}
+	detector := NewFraudDetector(ai, &mockRepo{}, &mockCache{}, publisher,
+		WithDetectors(NewTrustDetector(repo, TrustConfig{MinClean: 2, TTL: time.Hour})),
+		WithTrustedAISkip(),
+	)
+
+	now := time.Now()
+	for i, id := range []string{"tx-1", "tx-2", "tx-3"} {
+		tx := domain.Transaction{ID: id, UserID: "user-1", Amount: 900, Merchant: "Apple Store", Location: "Kyiv, Ukraine", Timestamp: now.Add(time.Duration(i) * 10 * time.Minute)}
+		if err := detector.Detect(context.Background(), tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if ai.calls != 2 {
+		t.Errorf("Expected the AI to be skipped once merchant and location were learned, got %d calls", ai.calls)
+	}
+	reasons := publisher.PublishedAlert.Reasons
+	if len(reasons) != 2 || reasons[0].Code != "TRUSTED_ENTITY" || reasons[0].Score != -30 || reasons[1].Code != "AI_SKIPPED" {
+		t.Errorf("Expected TRUSTED_ENTITY (-30) and AI_SKIPPED reasons, got %+v", reasons)
+	}
+}
+
+func TestTrustDetector_ManualEntryLowersScore(t *testing.T) {
+	merchant := domain.EntityRef{Type: domain.EntityMerchant, Value: "Apple Store"}
+	tx := domain.Transaction{ID: "tx-1", UserID: "user-1", Amount: 4000, Merchant: merchant.Value, Location: "Kyiv, Ukraine"}
+
+	untrusted := &mockPublisher{}
+	_ = NewFraudDetector(&mockAI{blocked: true}, &mockRepo{}, &mockCache{}, untrusted,
+		WithDetectors(NewTrustDetector(newMemoryTrustRepo(), DefaultTrustConfig())),
+	).Detect(context.Background(), tx)
+
+	trusted := &mockPublisher{}
+	_ = NewFraudDetector(&mockAI{blocked: true}, &mockRepo{}, &mockCache{}, trusted,
+		WithDetectors(NewTrustDetector(newMemoryTrustRepo(merchant), DefaultTrustConfig())),
+	).Detect(context.Background(), tx)
+
+	if untrusted.PublishedAlert.Decision != domain.DecisionBlock {
+		t.Fatalf("Expected BLOCK without trust, got %s (score %d)", untrusted.PublishedAlert.Decision, untrusted.PublishedAlert.RiskScore)
+	}
+	if got := trusted.PublishedAlert; got.RiskScore != untrusted.PublishedAlert.RiskScore-15 || got.Decision != domain.DecisionReview {
+		t.Errorf("Expected trusted merchant to lower the score by 15 to REVIEW, got %s (score %d)", got.Decision, got.RiskScore)
+	}
+}
+
+func TestTrustDetector_ReviewForgetsLearnedEntities(t *testing.T) {
+	merchant := domain.EntityRef{Type: domain.EntityMerchant, Value: "Apple Store"}
+	repo := newMemoryTrustRepo(merchant)
+	repo.entries[domain.EntityRef{Type: domain.EntityIP, Value: "198.51.100.4"}] = domain.TrustedEntity{
+		EntityType: domain.EntityIP, EntityValue: "198.51.100.4", Source: domain.TrustLearned, CleanCount: 9,
+	}
+	d := NewTrustDetector(repo, DefaultTrustConfig())
+
+	tx := domain.Transaction{UserID: "user-1", Merchant: merchant.Value, IP: "198.51.100.4"}
+	if err := d.Record(context.Background(), tx, domain.DecisionReview); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repo.entries) != 1 || repo.entries[merchant].Source != domain.TrustManual {
+		t.Errorf("Expected only the manual merchant entry to survive, got %+v", repo.entries)
+	}
+}
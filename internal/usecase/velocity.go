@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+type VelocityHorizon struct {
+	Name   string
+	Window time.Duration
+}
+
+// VelocityWindow aggregates the user's transactions within one horizon,
+// not counting the transaction being evaluated.
+type VelocityWindow domain.ActivityStats
+
+func DefaultVelocityHorizons() []VelocityHorizon {
+	return []VelocityHorizon{
+		{Name: "1m", Window: time.Minute},
+		{Name: "1h", Window: time.Hour},
+		{Name: "24h", Window: 24 * time.Hour},
+	}
+}
+
+// ParseVelocityHorizons parses a comma-separated list of durations such as
+// "1m,1h,24h". Each duration string doubles as the horizon name.
+func ParseVelocityHorizons(spec string) ([]VelocityHorizon, error) {
+	var horizons []VelocityHorizon
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		window, err := time.ParseDuration(name)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid velocity horizon %q", name)
+		}
+		horizons = append(horizons, VelocityHorizon{Name: name, Window: window})
+	}
+	if len(horizons) == 0 {
+		return nil, fmt.Errorf("no velocity horizons in %q", spec)
+	}
+	slices.SortFunc(horizons, func(a, b VelocityHorizon) int {
+		return int(a.Window - b.Window)
+	})
+	return horizons, nil
+}
+
+func velocityRetention(horizons []VelocityHorizon) time.Duration {
+	return horizons[len(horizons)-1].Window
+}
+
+// velocityWindows aggregates the shortest horizon from the activity itself;
+// stats holds the store's aggregates for the remaining horizons, in order.
+func velocityWindows(activity []domain.Activity, stats []domain.ActivityStats, now time.Time, horizons []VelocityHorizon) map[string]VelocityWindow {
+	windows := make(map[string]VelocityWindow, len(horizons))
+	windows[horizons[0].Name] = VelocityWindow(domain.SummarizeActivity(activity, now.Add(-horizons[0].Window), now))
+	for i, h := range horizons[1:] {
+		if i < len(stats) {
+			windows[h.Name] = VelocityWindow(stats[i])
+		}
+	}
+	return windows
+}
+
+// velocityStatWindows lists the horizons the store aggregates, all but the
+// shortest.
+func velocityStatWindows(horizons []VelocityHorizon) []time.Duration {
+	windows := make([]time.Duration, 0, len(horizons)-1)
+	for _, h := range horizons[1:] {
+		windows = append(windows, h.Window)
+	}
+	return windows
+}
+
+// velocityFeatures publishes features.velocity_<horizon>_{count,amount,locations}
+// and keeps features.velocity as the count in the shortest horizon.
+func velocityFeatures(windows map[string]VelocityWindow, horizons []VelocityHorizon, facts Facts) int {
+	for name, w := range windows {
+		prefix := "features.velocity_" + name
+		facts[prefix+"_count"] = w.Count
+		facts[prefix+"_amount"] = w.AmountSum
+		facts[prefix+"_locations"] = w.Locations
+	}
+	velocity := windows[horizons[0].Name].Count
+	facts["features.velocity"] = velocity
+	return velocity
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tokyosplif/fraud-core/internal/domain"
+)
+
+func TestVelocityWindows(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	activity := []domain.Activity{
+		{Amount: 10, Location: "Kyiv, Ukraine", At: now.Add(-10 * time.Second)},
+		{Amount: 20, Location: "Kyiv, Ukraine", At: now.Add(-30 * time.Second)},
+		{Amount: 30, Location: "Lviv, Ukraine", At: now.Add(-20 * time.Minute)},
+		{Amount: 40, Location: "Singapore", At: now.Add(-5 * time.Hour)},
+		{Amount: 50, Location: "Singapore", At: now.Add(-30 * time.Hour)},
+	}
+
+	horizons := DefaultVelocityHorizons()
+	var stats []domain.ActivityStats
+	for _, w := range velocityStatWindows(horizons) {
+		stats = append(stats, domain.SummarizeActivity(activity, now.Add(-w), now))
+	}
+	windows := velocityWindows(activity[:2], stats, now, horizons)
+
+	want := map[string]VelocityWindow{
+		"1m":  {Count: 2, AmountSum: 30, Locations: 1},
+		"1h":  {Count: 3, AmountSum: 60, Locations: 2},
+		"24h": {Count: 4, AmountSum: 100, Locations: 3},
+	}
+	for name, w := range want {
+		if windows[name] != w {
+			t.Errorf("%s: expected %+v, got %+v", name, w, windows[name])
+		}
+	}
+
+	facts := Facts{}
+	if vel := velocityFeatures(windows, horizons, facts); vel != 2 {
+		t.Errorf("Expected features.velocity to be the 1m count, got %d", vel)
+	}
+	if facts["features.velocity_24h_amount"] != 100.0 {
+		t.Errorf("Expected 24h amount feature, got %v", facts["features.velocity_24h_amount"])
+	}
+}
+
+func TestParseVelocityHorizons(t *testing.T) {
+	horizons, err := ParseVelocityHorizons("24h, 1m,1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(horizons) != 3 || horizons[0].Name != "1m" || horizons[2].Window != 24*time.Hour {
+		t.Errorf("Expected horizons sorted by window, got %+v", horizons)
+	}
+
+	if _, err := ParseVelocityHorizons("1m,soon"); err == nil {
+		t.Error("Expected error for invalid horizon")
+	}
+}